@@ -0,0 +1,254 @@
+// Package analysis 弹幕统计分析，用于绘制弹幕密度曲线、寻找高能时刻与热门弹幕
+//
+// 所有结果都是普通结构体，可以直接序列化后交给图表使用
+package analysis
+
+import (
+	"github.com/iyear/biligo"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Peak 高能时刻，即弹幕数最多的时间窗口
+type Peak struct {
+	Start int     `json:"start"` // 开始时间 单位为秒
+	End   int     `json:"end"`   // 结束时间(不含) 单位为秒
+	Count int     `json:"count"` // 窗口内的弹幕数
+	Ratio float64 `json:"ratio"` // 窗口内的每秒弹幕数与全片平均值之比
+}
+
+// Phrase 热门弹幕
+type Phrase struct {
+	Text     string   `json:"text"`     // 归一化后的弹幕
+	Count    int      `json:"count"`    // 出现次数
+	Variants []string `json:"variants"` // 出现过的原始写法 按出现次数降序 最多5个
+}
+
+// User 用户的发送情况
+type User struct {
+	MidHash string `json:"mid_hash"` // 发送者hash 可以用 biligo.CrackMidHash 反查
+	Count   int    `json:"count"`    // 发送弹幕数
+	First   int64  `json:"first"`    // 最早一条弹幕的出现位置 单位ms
+	Last    int64  `json:"last"`     // 最晚一条弹幕的出现位置 单位ms
+}
+
+// Density 每秒的弹幕数，下标为秒
+//
+// duration: 视频时长 单位为秒 传入0时使用最后一条弹幕的时间，超出时长的弹幕计入最后一秒
+func Density(dms []*biligo.Danmaku, duration int) []int {
+	if duration <= 0 {
+		for _, d := range dms {
+			if s := int(d.Progress/1000) + 1; s > duration {
+				duration = s
+			}
+		}
+	}
+	if duration <= 0 {
+		return nil
+	}
+	density := make([]int, duration)
+	for _, d := range dms {
+		s := int(d.Progress / 1000)
+		if s < 0 {
+			s = 0
+		}
+		if s >= duration {
+			s = duration - 1
+		}
+		density[s]++
+	}
+	return density
+}
+
+// Peaks 在密度曲线上寻找弹幕最多的n个时间窗口，窗口之间互不重叠，按弹幕数降序
+//
+// window: 窗口长度 单位为秒
+func Peaks(density []int, window int, n int) []*Peak {
+	if window <= 0 || n <= 0 || len(density) == 0 {
+		return nil
+	}
+	if window > len(density) {
+		window = len(density)
+	}
+
+	total := 0
+	for _, c := range density {
+		total += c
+	}
+	if total == 0 {
+		return nil
+	}
+	avg := float64(total) / float64(len(density))
+
+	// 以每一秒为起点的窗口弹幕数
+	sums := make([]int, len(density)-window+1)
+	cur := 0
+	for i, c := range density {
+		cur += c
+		if i >= window {
+			cur -= density[i-window]
+		}
+		if i >= window-1 {
+			sums[i-window+1] = cur
+		}
+	}
+
+	starts := make([]int, len(sums))
+	for i := range starts {
+		starts[i] = i
+	}
+	// 弹幕数相同时取更早的窗口
+	sort.SliceStable(starts, func(i, j int) bool {
+		return sums[starts[i]] > sums[starts[j]]
+	})
+
+	var peaks []*Peak
+	for _, s := range starts {
+		if len(peaks) == n || sums[s] == 0 {
+			break
+		}
+		overlap := false
+		for _, p := range peaks {
+			if s < p.End && p.Start < s+window {
+				overlap = true
+				break
+			}
+		}
+		if overlap {
+			continue
+		}
+		peaks = append(peaks, &Peak{
+			Start: s,
+			End:   s + window,
+			Count: sums[s],
+			Ratio: float64(sums[s]) / float64(window) / avg,
+		})
+	}
+	return peaks
+}
+
+// TopPhrases 出现次数最多的n条弹幕，按 Normalize 归一化后统计，次数相同时按文本排序
+func TopPhrases(dms []*biligo.Danmaku, n int) []*Phrase {
+	type stat struct {
+		count    int
+		variants map[string]int
+	}
+	stats := make(map[string]*stat)
+	for _, d := range dms {
+		text := Normalize(d.Content)
+		if text == "" {
+			continue
+		}
+		s, ok := stats[text]
+		if !ok {
+			s = &stat{variants: make(map[string]int)}
+			stats[text] = s
+		}
+		s.count++
+		s.variants[d.Content]++
+	}
+
+	phrases := make([]*Phrase, 0, len(stats))
+	for text, s := range stats {
+		p := &Phrase{Text: text, Count: s.count}
+		for v := range s.variants {
+			p.Variants = append(p.Variants, v)
+		}
+		sort.Slice(p.Variants, func(i, j int) bool {
+			a, b := s.variants[p.Variants[i]], s.variants[p.Variants[j]]
+			if a != b {
+				return a > b
+			}
+			return p.Variants[i] < p.Variants[j]
+		})
+		if len(p.Variants) > 5 {
+			p.Variants = p.Variants[:5]
+		}
+		phrases = append(phrases, p)
+	}
+	sort.Slice(phrases, func(i, j int) bool {
+		if phrases[i].Count != phrases[j].Count {
+			return phrases[i].Count > phrases[j].Count
+		}
+		return phrases[i].Text < phrases[j].Text
+	})
+	if n > 0 && len(phrases) > n {
+		phrases = phrases[:n]
+	}
+	return phrases
+}
+
+// Users 每个发送者的弹幕数，按弹幕数降序，数量相同时按hash排序
+func Users(dms []*biligo.Danmaku) []*User {
+	m := make(map[string]*User)
+	for _, d := range dms {
+		if d.MidHash == "" {
+			continue
+		}
+		u, ok := m[d.MidHash]
+		if !ok {
+			u = &User{MidHash: d.MidHash, First: d.Progress, Last: d.Progress}
+			m[d.MidHash] = u
+		}
+		u.Count++
+		if d.Progress < u.First {
+			u.First = d.Progress
+		}
+		if d.Progress > u.Last {
+			u.Last = d.Progress
+		}
+	}
+
+	users := make([]*User, 0, len(m))
+	for _, u := range m {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].Count != users[j].Count {
+			return users[i].Count > users[j].Count
+		}
+		return users[i].MidHash < users[j].MidHash
+	})
+	return users
+}
+
+// Normalize 归一化弹幕文本，使同一含义的不同写法统计为同一条
+//
+// 全角转半角、英文转小写、去除空白与句末标点，连续重复3次以上的字符压缩为3个，
+// 如 "233" "23333333" 都归一为 "2333"，"哈哈哈哈哈" 归一为 "哈哈哈"
+func Normalize(s string) string {
+	var (
+		b    strings.Builder
+		last rune
+		run  int
+	)
+	for _, r := range s {
+		// 全角字符转半角
+		if r == '　' {
+			r = ' '
+		} else if r >= '！' && r <= '～' {
+			r -= 0xFEE0
+		}
+		if unicode.IsSpace(r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if r == last {
+			run++
+		} else {
+			last, run = r, 1
+		}
+		if run > 3 {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	s = strings.TrimRightFunc(b.String(), func(r rune) bool {
+		return strings.ContainsRune("!?.~,。…、", r)
+	})
+	if s == "233" {
+		return "2333"
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"github.com/iyear/biligo"
+	"testing"
+)
+
+func newTestDanmaku() []*biligo.Danmaku {
+	var dms []*biligo.Danmaku
+	add := func(sec int64, content string, hash string) {
+		dms = append(dms, &biligo.Danmaku{Progress: sec*1000 + 500, Content: content, MidHash: hash})
+	}
+	add(0, "前排", "a")
+	add(1, "233", "b")
+	// 10-14秒为高能时刻
+	for i := int64(10); i < 15; i++ {
+		add(i, "23333333", "a")
+		add(i, "２３３３", "b")
+		add(i, "哈哈哈哈哈", "c")
+	}
+	add(30, "哈哈哈！", "c")
+	add(59, "完结撒花", "d")
+	return dms
+}
+
+func TestDensity(t *testing.T) {
+	d := Density(newTestDanmaku(), 0)
+	if len(d) != 60 || d[0] != 1 || d[10] != 3 || d[59] != 1 {
+		t.Error(d)
+		t.FailNow()
+	}
+	// 超出时长的计入最后一秒
+	if d = Density(newTestDanmaku(), 20); len(d) != 20 || d[19] != 2 {
+		t.Error(d)
+		t.FailNow()
+	}
+	if d = Density(nil, 0); d != nil {
+		t.Error(d)
+		t.FailNow()
+	}
+}
+func TestPeaks(t *testing.T) {
+	peaks := Peaks(Density(newTestDanmaku(), 0), 5, 2)
+	if len(peaks) != 2 {
+		t.Errorf("peaks: %d", len(peaks))
+		t.FailNow()
+	}
+	if p := peaks[0]; p.Start != 10 || p.End != 15 || p.Count != 15 {
+		t.Errorf("%+v", p)
+		t.FailNow()
+	}
+	// 第二个窗口不能与第一个重叠
+	if p := peaks[1]; p.End > 10 && p.Start < 15 {
+		t.Errorf("%+v", p)
+		t.FailNow()
+	}
+	if peaks[0].Ratio <= 1 {
+		t.Error(peaks[0].Ratio)
+		t.FailNow()
+	}
+}
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"23333333":   "2333",
+		"２３３３":       "2333",
+		"哈哈哈哈哈":      "哈哈哈",
+		"哈哈哈！":       "哈哈哈",
+		"AWSL  awsl": "awslawsl",
+		"!!!":        "",
+	} {
+		if got := Normalize(in); got != want {
+			t.Errorf("in: %s,want: %s,got: %s", in, want, got)
+			t.FailNow()
+		}
+	}
+}
+func TestTopPhrases(t *testing.T) {
+	phrases := TopPhrases(newTestDanmaku(), 2)
+	if len(phrases) != 2 {
+		t.Errorf("phrases: %d", len(phrases))
+		t.FailNow()
+	}
+	if p := phrases[0]; p.Text != "2333" || p.Count != 11 || p.Variants[0] != "23333333" {
+		t.Errorf("%+v", p)
+		t.FailNow()
+	}
+	if p := phrases[1]; p.Text != "哈哈哈" || p.Count != 6 {
+		t.Errorf("%+v", p)
+		t.FailNow()
+	}
+}
+func TestUsers(t *testing.T) {
+	users := Users(newTestDanmaku())
+	if len(users) != 4 {
+		t.Errorf("users: %d", len(users))
+		t.FailNow()
+	}
+	if u := users[0]; u.MidHash != "a" || u.Count != 6 || u.First != 500 || u.Last != 14500 {
+		t.Errorf("%+v", u)
+		t.FailNow()
+	}
+}
@@ -187,3 +187,36 @@ func (h *baseClient) upload(base, endpoint string, payload map[string]string, fi
 	// 文件不输出，否则全是乱码
 	return h.request(req, payload)
 }
+
+// download 下载资源文件，如快照拼版、pvdata、字幕等
+//
+// 兼容B站返回的以 // 开头的链接
+func (h *baseClient) download(link string) ([]byte, error) {
+	if strings.HasPrefix(link, "//") {
+		link = "https:" + link
+	}
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Referer", "https://www.bilibili.com")
+	req.Header.Add("User-Agent", h.ua)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", link, resp.Status)
+	}
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if h.debug {
+		h.logger.Printf("GET %s  %d bytes", link, len(raw))
+	}
+	return raw, nil
+}
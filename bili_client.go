@@ -3,7 +3,6 @@ package biligo
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/golang/protobuf/proto"
 	"github.com/iyear/biligo/internal/util"
 	"github.com/iyear/biligo/proto/dm"
 	"github.com/pkg/errors"
@@ -1351,6 +1350,8 @@ func (b *BiliClient) VideoReportProgress(aid int64, cid int64, progress int64) e
 // VideoGetPlayURL 获取视频取流地址
 //
 // 所有参数、返回信息和取流方法的说明请直接前往：https://github.com/SocialSisterYi/bilibili-API-collect/blob/master/video/videostream_url.md
+//
+// qn 与 fnval 可以使用 NewPlayURLParam 构造，dash流可以使用 BestVideo BestAudio 挑选
 func (b *BiliClient) VideoGetPlayURL(aid int64, cid int64, qn int, fnval int) (*VideoPlayURLResult, error) {
 	resp, err := b.RawParse(
 		BiliApiURL,
@@ -1627,6 +1628,17 @@ func (b *BiliClient) DanmakuGetHistoryIndex(cid int64, year int, month int) ([]s
 //
 // date 历史日期 YYYY-MM-DD
 func (b *BiliClient) DanmakuGetHistory(cid int64, date string) (*DanmakuResp, error) {
+	reply, err := b.DanmakuGetHistoryReply(cid, date)
+	if err != nil {
+		return nil, err
+	}
+	return NewDanmakuResp(reply), nil
+}
+
+// DanmakuGetHistoryReply 获取历史弹幕的原始protobuf结构，可用于读取未解析到 DanmakuResp 的字段
+//
+// date 历史日期 YYYY-MM-DD
+func (b *BiliClient) DanmakuGetHistoryReply(cid int64, date string) (*dm.DmSegMobileReply, error) {
 	resp, err := b.Raw(
 		BiliApiURL,
 		"x/v2/dm/web/history/seg.so",
@@ -1640,29 +1652,36 @@ func (b *BiliClient) DanmakuGetHistory(cid int64, date string) (*DanmakuResp, er
 	if err != nil {
 		return nil, err
 	}
-	var reply dm.DmSegMobileReply
-	var r = &DanmakuResp{}
-	if err := proto.Unmarshal(resp, &reply); err != nil {
+	return decodeDanmakuSeg(resp)
+}
+
+// DanmakuGetByPb
+//
+// 获取实时弹幕(protobuf接口)，携带登录信息请求
+func (b *BiliClient) DanmakuGetByPb(tp int, cid int64, seg int) (*DanmakuResp, error) {
+	reply, err := b.DanmakuGetByPbReply(tp, cid, seg)
+	if err != nil {
 		return nil, err
 	}
-	for _, elem := range reply.GetElems() {
-		r.Danmaku = append(r.Danmaku, &Danmaku{
-			ID:       uint64(elem.Id),
-			Progress: int64(elem.Progress),
-			Mode:     int(elem.Mode),
-			FontSize: int(elem.Fontsize),
-			Color:    int(elem.Color),
-			MidHash:  elem.MidHash,
-			Content:  elem.Content,
-			Ctime:    elem.Ctime,
-			Weight:   int(elem.Weight),
-			Action:   elem.Action,
-			Pool:     int(elem.Pool),
-			IDStr:    elem.IdStr,
-			Attr:     int(elem.Attr),
-		})
+	return NewDanmakuResp(reply), nil
+}
+
+// DanmakuGetByPbReply 获取实时弹幕的原始protobuf结构，携带登录信息请求，可用于读取未解析到 DanmakuResp 的字段
+func (b *BiliClient) DanmakuGetByPbReply(tp int, cid int64, seg int) (*dm.DmSegMobileReply, error) {
+	resp, err := b.Raw(
+		BiliApiURL,
+		"x/v2/dm/web/seg.so",
+		"GET",
+		map[string]string{
+			"type":          strconv.Itoa(tp),
+			"oid":           strconv.FormatInt(cid, 10),
+			"segment_index": strconv.Itoa(seg),
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
-	return r, nil
+	return decodeDanmakuSeg(resp)
 }
 
 // DanmakuPost 发送普通弹幕
@@ -2681,6 +2700,34 @@ func (b *BiliClient) DynaGetDrafts() (*DynaGetDraft, error) {
 	return r, nil
 }
 
+// LiveGetWsConf 获取直播websocket服务器信息，携带登录信息请求
+//
+// roomID: 真实直播间ID
+func (b *BiliClient) LiveGetWsConf(roomID int64) (*LiveWsConf, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"room/v1/Danmu/getConf",
+		"GET",
+		map[string]string{
+			"room_id": strconv.FormatInt(roomID, 10),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveWsConf{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// 发送直播弹幕时被屏蔽，可以用 errors.Is 判断
+var (
+	ErrLiveDanmakuShielded     = errors.New("(0) 弹幕包含屏蔽词")
+	ErrLiveDanmakuRoomShielded = errors.New("(0) 弹幕包含直播间指定屏蔽词")
+)
+
 // LiveSendDanmaku 发送弹幕
 //
 // roomID: 真实直播间ID
@@ -2694,31 +2741,609 @@ func (b *BiliClient) DynaGetDrafts() (*DynaGetDraft, error) {
 // msg: 弹幕内容
 //
 // bubble: 气泡弹幕?默认0
+//
+// 弹幕被屏蔽时返回 ErrLiveDanmakuShielded 或 ErrLiveDanmakuRoomShielded
 func (b *BiliClient) LiveSendDanmaku(roomID int64, color int64, fontsize int, mode int, msg string, bubble int) error {
+	return b.liveSendDanmaku(map[string]string{
+		"roomid":   strconv.FormatInt(roomID, 10),
+		"color":    strconv.FormatInt(color, 10),
+		"fontsize": strconv.Itoa(fontsize),
+		"mode":     strconv.Itoa(mode),
+		"msg":      msg,
+		"bubble":   strconv.Itoa(bubble),
+		"rnd":      strconv.FormatInt(time.Now().Unix(), 10),
+	})
+}
+
+// LiveSendEmoticon 发送表情弹幕
+//
+// emoticon: 表情的 emoticon_unique 如 official_147，与 LiveEmoticon.Unique 相同
+func (b *BiliClient) LiveSendEmoticon(roomID int64, emoticon string) error {
+	return b.liveSendDanmaku(map[string]string{
+		"roomid":   strconv.FormatInt(roomID, 10),
+		"color":    "16777215",
+		"fontsize": "25",
+		"mode":     "1",
+		"msg":      emoticon,
+		"dm_type":  "1",
+		"bubble":   "0",
+		"rnd":      strconv.FormatInt(time.Now().Unix(), 10),
+	})
+}
+func (b *BiliClient) liveSendDanmaku(payload map[string]string) error {
 	resp, err := b.RawParse(
 		BiliLiveURL,
 		"msg/send",
 		"POST",
+		payload,
+	)
+	if err != nil {
+		return err
+	}
+	switch resp.Message {
+	case "f":
+		return ErrLiveDanmakuShielded
+	case "k":
+		return ErrLiveDanmakuRoomShielded
+	}
+	return nil
+}
+
+// 礼物的瓜子类型 即 LiveSendGift 的coinType参数
+const (
+	LiveCoinGold   = "gold"   // 金瓜子
+	LiveCoinSilver = "silver" // 银瓜子
+)
+
+// LiveSendGift 赠送金瓜子或银瓜子礼物
+//
+// ruid: 主播mid
+//
+// giftID price coinType: 从 LiveGetAllGiftInfo 获取，price为单价
+//
+// coinType: LiveCoinGold 或 LiveCoinSilver，其他值返回错误
+func (b *BiliClient) LiveSendGift(roomID int64, ruid int64, giftID int, num int, price int, coinType string) (*LiveSendGiftResult, error) {
+	var endpoint string
+	switch coinType {
+	case LiveCoinGold:
+		endpoint = "xlive/revenue/v2/gift/sendGold"
+	case LiveCoinSilver:
+		endpoint = "xlive/revenue/v2/gift/sendSilver"
+	default:
+		return nil, errors.Errorf("invalid coin type: %s", coinType)
+	}
+	return b.liveSendGift(endpoint, map[string]string{
+		"biz_id":    strconv.FormatInt(roomID, 10),
+		"ruid":      strconv.FormatInt(ruid, 10),
+		"gift_id":   strconv.Itoa(giftID),
+		"gift_num":  strconv.Itoa(num),
+		"price":     strconv.Itoa(price),
+		"coin_type": coinType,
+		"bag_id":    "0",
+	})
+}
+
+// LiveSendBagGift 赠送背包中的礼物
+//
+// bagID giftID: 从 LiveGetBagList 获取
+func (b *BiliClient) LiveSendBagGift(roomID int64, ruid int64, bagID int64, giftID int, num int) (*LiveSendGiftResult, error) {
+	return b.liveSendGift("xlive/revenue/v2/gift/sendBag", map[string]string{
+		"biz_id":   strconv.FormatInt(roomID, 10),
+		"ruid":     strconv.FormatInt(ruid, 10),
+		"gift_id":  strconv.Itoa(giftID),
+		"gift_num": strconv.Itoa(num),
+		"price":    "0",
+		"bag_id":   strconv.FormatInt(bagID, 10),
+	})
+}
+func (b *BiliClient) liveSendGift(endpoint string, payload map[string]string) (*LiveSendGiftResult, error) {
+	payload["uid"] = strconv.FormatInt(b.Me.MID, 10)
+	payload["send_ruid"] = "0"
+	payload["platform"] = "pc"
+	payload["biz_code"] = "Live"
+	payload["storm_beat_id"] = "0"
+	payload["csrf_token"] = b.auth.BiliJCT
+	resp, err := b.RawParse(BiliLiveURL, endpoint, "POST", payload)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveSendGiftResult{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveGetBagList 获取背包礼物
+//
+// roomID: 部分礼物只能在指定直播间使用，传入0获取所有礼物
+func (b *BiliClient) LiveGetBagList(roomID int64) ([]*LiveBagGift, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-room/v1/gift/bag_list",
+		"GET",
 		map[string]string{
-			"roomid":   strconv.FormatInt(roomID, 10),
-			"color":    strconv.FormatInt(color, 10),
-			"fontsize": strconv.Itoa(fontsize),
-			"mode":     strconv.Itoa(mode),
-			"msg":      msg,
-			"bubble":   strconv.Itoa(bubble),
-			"rnd":      strconv.FormatInt(time.Now().Unix(), 10),
+			"t":       strconv.FormatInt(time.Now().UnixNano()/1e6, 10),
+			"room_id": strconv.FormatInt(roomID, 10),
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if resp.Message == "f" {
-		return errors.New("(0) 弹幕包含屏蔽词")
+	var r struct {
+		List []*LiveBagGift `json:"list"`
 	}
-	if resp.Message == "k" {
-		return errors.New("(0) 弹幕包含直播间指定屏蔽词")
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
 	}
-	return nil
+	return r.List, nil
+}
+
+// LiveWearMedal 佩戴粉丝勋章
+//
+// medalID: 从 LiveGetMyMedals 获取
+func (b *BiliClient) LiveWearMedal(medalID int64) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-room/v1/fansMedal/wear",
+		"POST",
+		map[string]string{
+			"medal_id":   strconv.FormatInt(medalID, 10),
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveUnwearMedal 取消佩戴粉丝勋章
+func (b *BiliClient) LiveUnwearMedal() error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-room/v1/fansMedal/take_off",
+		"POST",
+		map[string]string{
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveGetMyMedals 获取自己的粉丝勋章
+//
+// pn: 页码 从1开始
+//
+// ps: 每页数量 最大50
+func (b *BiliClient) LiveGetMyMedals(pn int, ps int) (*LiveMyMedals, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/app-ucenter/v1/user/GetMyMedals",
+		"GET",
+		map[string]string{
+			"page":      strconv.Itoa(pn),
+			"page_size": strconv.Itoa(ps),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveMyMedals{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveSign 直播每日签到
+//
+// 今日已签到时返回错误
+func (b *BiliClient) LiveSign() (*LiveSignResult, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/sign/DoSign",
+		"GET",
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveSignResult{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveLike 直播间点赞
+//
+// anchorID: 主播mid
+//
+// count: 点赞次数，与连续点击相同
+func (b *BiliClient) LiveLike(roomID int64, anchorID int64, count int) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/app-ucenter/v1/like_info_v3/like/likeReportV3",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"anchor_id":  strconv.FormatInt(anchorID, 10),
+			"uid":        strconv.FormatInt(b.Me.MID, 10),
+			"click_time": strconv.Itoa(count),
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveGetReplayList 获取自己的直播回放列表
+//
+// pn: 页码 从1开始
+//
+// ps: 每页项数
+func (b *BiliClient) LiveGetReplayList(pn int, ps int) (*LiveReplayList, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/app-blink/v1/anchorVideo/AnchorGetReplayList",
+		"GET",
+		map[string]string{
+			"page":      strconv.Itoa(pn),
+			"page_size": strconv.Itoa(ps),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveReplayList{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveGetRecommend 获取首页推荐的直播间，每次请求的结果不同
+func (b *BiliClient) LiveGetRecommend() ([]*LiveRecommendRoom, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-interface/v1/webMain/getMoreRecList",
+		"GET",
+		map[string]string{
+			"platform": "web",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		RecommendRoomList []*LiveRecommendRoom `json:"recommend_room_list"`
+	}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r.RecommendRoomList, nil
+}
+
+// LiveGetFollowingLive 获取关注的主播中正在直播的直播间
+//
+// pn: 页码 从1开始
+//
+// ps: 每页项数 最大10
+func (b *BiliClient) LiveGetFollowingLive(pn int, ps int) (*LiveFollowingList, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/xfetter/GetWebList",
+		"GET",
+		map[string]string{
+			"page":      strconv.Itoa(pn),
+			"page_size": strconv.Itoa(ps),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveFollowingList{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveStart 开始直播，返回推流地址
+//
+// roomID: 自己的真实直播间ID
+//
+// area: 直播分区ID 即子分区的ID
+func (b *BiliClient) LiveStart(roomID int64, area int) (*LiveStartResult, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"room/v1/Room/startLive",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"area_v2":    strconv.Itoa(area),
+			"platform":   "pc",
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveStartResult{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveStop 结束直播
+//
+// 返回直播状态是否改变，未开播时为false
+func (b *BiliClient) LiveStop(roomID int64) (bool, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"room/v1/Room/stopLive",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"platform":   "pc",
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return gjson.GetBytes(resp.Data, "change").Int() == 1, nil
+}
+
+// liveUpdateRoom 修改直播间信息，只修改传入的字段
+func (b *BiliClient) liveUpdateRoom(roomID int64, payload map[string]string) error {
+	payload["room_id"] = strconv.FormatInt(roomID, 10)
+	payload["csrf_token"] = b.auth.BiliJCT
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"room/v1/Room/update",
+		"POST",
+		payload,
+	)
+	return err
+}
+
+// LiveUpdateTitle 修改直播间标题
+func (b *BiliClient) LiveUpdateTitle(roomID int64, title string) error {
+	return b.liveUpdateRoom(roomID, map[string]string{"title": title})
+}
+
+// LiveUpdateArea 修改直播间分区
+//
+// area: 直播分区ID 即子分区的ID
+func (b *BiliClient) LiveUpdateArea(roomID int64, area int) error {
+	return b.liveUpdateRoom(roomID, map[string]string{"area_id": strconv.Itoa(area)})
+}
+
+// LiveAddTag 添加直播间标签
+func (b *BiliClient) LiveAddTag(roomID int64, tag string) error {
+	return b.liveUpdateRoom(roomID, map[string]string{"add_tag": tag})
+}
+
+// LiveDelTag 删除直播间标签
+func (b *BiliClient) LiveDelTag(roomID int64, tag string) error {
+	return b.liveUpdateRoom(roomID, map[string]string{"del_tag": tag})
+}
+
+// LiveUpdateNews 修改直播间公告
+//
+// 删除公告留空即可 少于60字符
+func (b *BiliClient) LiveUpdateNews(roomID int64, content string) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/app-blink/v1/index/updateRoomNews",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"uid":        strconv.FormatInt(b.Me.MID, 10),
+			"content":    content,
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveUploadCover 上传直播间封面图片
+//
+// 返回图片url，用于 LiveUpdateCover
+func (b *BiliClient) LiveUploadCover(cover io.Reader) (string, error) {
+	resp, err := b.UploadParse(
+		BiliApiURL,
+		"x/upload/web/image",
+		map[string]string{
+			"bucket": "live",
+			"dir":    "new_room_cover",
+		},
+		[]*FileUpload{{
+			Field: "file",
+			Name:  "cover.jpg", // B站通过文件头判断content-type，该字段无用
+			File:  cover,
+		}},
+	)
+	if err != nil {
+		return "", err
+	}
+	return gjson.GetBytes(resp.Data, "location").String(), nil
+}
+
+// LiveUpdateCover 修改直播间封面，修改后需要审核
+//
+// cover: 图片url 从 LiveUploadCover 获取
+func (b *BiliClient) LiveUpdateCover(roomID int64, cover string) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"room/v1/Cover/replace",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"url":        cover,
+			"type":       "cover",
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveGetAdmins 获取自己直播间的房管列表
+//
+// pn: 页码 从1开始
+func (b *BiliClient) LiveGetAdmins(pn int) (*LiveAdminList, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/roomAdmin/get_by_anchor",
+		"GET",
+		map[string]string{
+			"page": strconv.Itoa(pn),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveAdminList{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveAddAdmin 任命自己直播间的房管
+func (b *BiliClient) LiveAddAdmin(uid int64) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/roomAdmin/appoint",
+		"POST",
+		map[string]string{
+			"admin":       strconv.FormatInt(uid, 10),
+			"admin_level": "1",
+			"csrf_token":  b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveDelAdmin 撤销自己直播间的房管
+func (b *BiliClient) LiveDelAdmin(uid int64) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/app-ucenter/v1/roomAdmin/dismiss",
+		"POST",
+		map[string]string{
+			"uid":        strconv.FormatInt(uid, 10),
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveMuteUser 禁言用户 需要是主播或房管
+//
+// hour: 禁言时长 单位为小时 -1:永久 0:本场直播
+func (b *BiliClient) LiveMuteUser(roomID int64, uid int64, hour int) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/banned/AddSilentUser",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"tuid":       strconv.FormatInt(uid, 10),
+			"mobile_app": "web",
+			"hour":       strconv.Itoa(hour),
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveUnmuteUser 解除禁言
+func (b *BiliClient) LiveUnmuteUser(roomID int64, uid int64) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/banned/DelSilentUser",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"tuid":       strconv.FormatInt(uid, 10),
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveGetMutedUsers 获取直播间禁言列表
+//
+// pn: 页码 从1开始
+func (b *BiliClient) LiveGetMutedUsers(roomID int64, pn int) (*LiveMutedUsers, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/banned/GetSilentUserList",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"ps":         strconv.Itoa(pn),
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveMutedUsers{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveAddShieldKeyword 添加直播间屏蔽词，包含屏蔽词的弹幕不会显示
+func (b *BiliClient) LiveAddShieldKeyword(roomID int64, keyword string) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/banned/AddShieldKeyword",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"keyword":    keyword,
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveDelShieldKeyword 删除直播间屏蔽词
+func (b *BiliClient) LiveDelShieldKeyword(roomID int64, keyword string) error {
+	_, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/banned/DelShieldKeyword",
+		"POST",
+		map[string]string{
+			"room_id":    strconv.FormatInt(roomID, 10),
+			"keyword":    keyword,
+			"csrf_token": b.auth.BiliJCT,
+		},
+	)
+	return err
+}
+
+// LiveGetShieldKeywords 获取直播间屏蔽词列表
+func (b *BiliClient) LiveGetShieldKeywords(roomID int64) ([]string, error) {
+	resp, err := b.RawParse(
+		BiliLiveURL,
+		"xlive/web-ucenter/v1/banned/GetShieldKeywordList",
+		"GET",
+		map[string]string{
+			"room_id": strconv.FormatInt(roomID, 10),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var keywords []string
+	for _, k := range gjson.GetBytes(resp.Data, "keyword_list.#.keyword").Array() {
+		keywords = append(keywords, k.String())
+	}
+	return keywords, nil
 }
 func (b *BiliClient) UserGetInfo(mid int64) (*UserInfo, error) {
 	resp, err := b.RawParse(
@@ -2738,3 +3363,165 @@ func (b *BiliClient) UserGetInfo(mid int64) (*UserInfo, error) {
 	}
 	return r, nil
 }
+
+// BangumiGetPlayURL 获取番剧分集取流地址
+//
+// 参数与返回值同 VideoGetPlayURL，额外需要分集epid
+//
+// 大会员可以获取会员专享分集和更高清晰度
+func (b *BiliClient) BangumiGetPlayURL(aid int64, cid int64, epid int64, qn int, fnval int) (*VideoPlayURLResult, error) {
+	resp, err := b.RawParse(
+		BiliApiURL,
+		"pgc/player/web/playurl",
+		"GET",
+		map[string]string{
+			"avid":  strconv.FormatInt(aid, 10),
+			"cid":   strconv.FormatInt(cid, 10),
+			"ep_id": strconv.FormatInt(epid, 10),
+			"qn":    strconv.Itoa(qn),
+			"fnval": strconv.Itoa(fnval),
+			"fnver": "0",
+			"fourk": "1",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r *VideoPlayURLResult
+	if err = json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// BangumiFollow 追番/追剧
+//
+// ssid: 剧集ssid
+//
+// follow: true:追番 false:取消追番
+//
+// 成功后返回提示文字 如 "自己追的番就要好好看完哟^o^"
+func (b *BiliClient) BangumiFollow(ssid int64, follow bool) (string, error) {
+	resp, err := b.RawParse(
+		BiliApiURL,
+		util.IF(follow, "pgc/web/follow/add", "pgc/web/follow/del").(string),
+		"POST",
+		map[string]string{
+			"season_id": strconv.FormatInt(ssid, 10),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	var r struct {
+		Toast string `json:"toast"`
+	}
+	if err = json.Unmarshal(resp.Result, &r); err != nil {
+		return "", err
+	}
+	return r.Toast, nil
+}
+
+// SubtitleSubmit 为视频提交字幕，提交后进入审核
+//
+// 只能对 VideoSubtitle.AllowSubmit 为true的视频提交
+//
+// lan: 字幕语言 如 zh-CN en-US ja
+//
+// sign: 是否署名
+//
+// 返回字幕id
+func (b *BiliClient) SubtitleSubmit(aid int64, cid int64, lan string, s *Subtitle, sign bool) (int64, error) {
+	return b.subtitleSave(aid, cid, lan, s, true, sign)
+}
+
+// SubtitleSaveDraft 将字幕保存为草稿，不会进入审核
+//
+// 参数同 SubtitleSubmit，返回字幕id
+func (b *BiliClient) SubtitleSaveDraft(aid int64, cid int64, lan string, s *Subtitle, sign bool) (int64, error) {
+	return b.subtitleSave(aid, cid, lan, s, false, sign)
+}
+func (b *BiliClient) subtitleSave(aid int64, cid int64, lan string, s *Subtitle, submit bool, sign bool) (int64, error) {
+	data, err := s.BCC()
+	if err != nil {
+		return 0, err
+	}
+	resp, err := b.RawParse(
+		BiliApiURL,
+		"x/v2/dm/subtitle/draft/save",
+		"POST",
+		map[string]string{
+			"type":   "1",
+			"oid":    strconv.FormatInt(cid, 10),
+			"bvid":   AV2BV(aid),
+			"lan":    lan,
+			"data":   string(data),
+			"submit": strconv.FormatBool(submit),
+			"sign":   strconv.FormatBool(sign),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return gjson.Get(string(resp.Data), "subtitle_id").Int(), nil
+}
+
+// SubtitleGetMy 获取自己提交的字幕列表
+//
+// status: 筛选状态 0：全部 其他见 SubtitleStatusDraft 等
+//
+// pn: 页码
+//
+// ps: 每页项数
+func (b *BiliClient) SubtitleGetMy(status int, pn int, ps int) (*SubtitleList, error) {
+	resp, err := b.RawParse(
+		BiliApiURL,
+		"x/v2/dm/subtitle/search/author/list",
+		"GET",
+		map[string]string{
+			"status": util.IF(status == 0, "", strconv.Itoa(status)).(string),
+			"page":   strconv.Itoa(pn),
+			"size":   strconv.Itoa(ps),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &SubtitleList{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SubtitleWithdraw 撤回待审核的字幕，撤回后变为草稿
+//
+// subtitleID: 字幕id
+func (b *BiliClient) SubtitleWithdraw(cid int64, subtitleID int64) error {
+	_, err := b.RawParse(
+		BiliApiURL,
+		"x/v2/dm/subtitle/author/withdraw",
+		"POST",
+		map[string]string{
+			"oid":         strconv.FormatInt(cid, 10),
+			"subtitle_id": strconv.FormatInt(subtitleID, 10),
+		},
+	)
+	return err
+}
+
+// SubtitleDel 删除自己的字幕
+//
+// subtitleID: 字幕id
+func (b *BiliClient) SubtitleDel(cid int64, subtitleID int64) error {
+	_, err := b.RawParse(
+		BiliApiURL,
+		"x/v2/dm/subtitle/del",
+		"POST",
+		map[string]string{
+			"oid":         strconv.FormatInt(cid, 10),
+			"subtitle_id": strconv.FormatInt(subtitleID, 10),
+		},
+	)
+	return err
+}
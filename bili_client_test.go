@@ -2,10 +2,16 @@ package biligo
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/iyear/biligo/livetest"
+	"github.com/pkg/errors"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 )
 
 var testBiliClient *BiliClient
@@ -392,6 +398,29 @@ func TestBiliClient_DanmakuHistoryGet(t *testing.T) {
 	}
 	t.Logf("num: %d", len(r.Danmaku))
 }
+func TestBiliClient_DanmakuGetByPb(t *testing.T) {
+	r, err := testBiliClient.DanmakuGetByPb(1, 1176840, 1)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("num: %d", len(r.Danmaku))
+}
+func TestBiliClient_DanmakuCrawlHistory(t *testing.T) {
+	r, err := testBiliClient.DanmakuCrawlHistory(810872, 1176840, &DanmakuHistorySetting{
+		Interval:   2 * time.Second,
+		Checkpoint: filepath.Join(t.TempDir(), "history_1176840.json"),
+		WithLive:   true,
+		OnDate: func(date string, total int) {
+			t.Logf("date: %s,total: %d", date, total)
+		},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("num: %d", len(r.Danmaku))
+}
 func TestBiliClient_ChannelAdd(t *testing.T) {
 	cid, err := testBiliClient.ChanAdd("test", "testtest")
 	if err != nil {
@@ -942,6 +971,198 @@ func TestBiliClient_LiveSendDanmaku(t *testing.T) {
 		t.FailNow()
 	}
 }
+func TestLiveSendDanmakuShielded(t *testing.T) {
+	s := livetest.NewServer(23713127)
+	defer s.Close()
+	s.HandleData("x/member/web/account", map[string]interface{}{"mid": 1})
+	var msg string
+	s.Handle("msg/send", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"code":0,"message":%q,"data":{}}`, msg)
+	})
+	c, err := NewBiliClient(&BiliSetting{Auth: &CookieAuth{}, Client: s.Client()})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for m, want := range map[string]error{"": nil, "f": ErrLiveDanmakuShielded, "k": ErrLiveDanmakuRoomShielded} {
+		msg = m
+		if err = c.LiveSendDanmaku(s.RoomID, 16777215, 25, 1, "test", 0); !errors.Is(err, want) {
+			t.Errorf("%q: %v", m, err)
+		}
+		if err = c.LiveSendEmoticon(s.RoomID, "official_147"); !errors.Is(err, want) {
+			t.Errorf("%q: %v", m, err)
+		}
+	}
+}
+func TestLiveSendGiftCoinType(t *testing.T) {
+	s := livetest.NewServer(23713127)
+	defer s.Close()
+	s.HandleData("x/member/web/account", map[string]interface{}{"mid": 1})
+	var got []string
+	for _, coin := range []string{"Gold", "Silver"} {
+		endpoint := "xlive/revenue/v2/gift/send" + coin
+		s.Handle(endpoint, func(w http.ResponseWriter, r *http.Request) {
+			_ = r.ParseForm()
+			got = append(got, endpoint+":"+r.PostForm.Get("coin_type"))
+			livetest.WriteData(w, map[string]interface{}{"gift_name": "辣条"})
+		})
+	}
+	c, err := NewBiliClient(&BiliSetting{Auth: &CookieAuth{}, Client: s.Client()})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, coin := range []string{LiveCoinGold, LiveCoinSilver} {
+		if _, err = c.LiveSendGift(s.RoomID, 1, 1, 1, 100, coin); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	if len(got) != 2 || got[0] != "xlive/revenue/v2/gift/sendGold:gold" || got[1] != "xlive/revenue/v2/gift/sendSilver:silver" {
+		t.Errorf("%v", got)
+	}
+	// 大小写不同或拼写错误时不发送请求
+	for _, coin := range []string{"Gold", "sliver", ""} {
+		if _, err = c.LiveSendGift(s.RoomID, 1, 1, 1, 100, coin); err == nil {
+			t.Errorf("%q: want error", coin)
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("%v", got)
+	}
+}
+func TestBiliClient_LiveSendGift(t *testing.T) {
+	r, err := testBiliClient.LiveSendGift(23713127, 1392039, 1, 1, 100, LiveCoinSilver)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("gift: %s,num: %d", r.GiftName, r.GiftNum)
+}
+func TestBiliClient_LiveGetBagList(t *testing.T) {
+	r, err := testBiliClient.LiveGetBagList(0)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, g := range r {
+		t.Logf("bag: %d,gift: %s,num: %d,expire: %d,%s", g.BagID, g.GiftName, g.GiftNum, g.ExpireAt, g.CornerMark)
+	}
+}
+func TestBiliClient_LiveGetMyMedals(t *testing.T) {
+	r, err := testBiliClient.LiveGetMyMedals(1, 10)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, m := range r.Items {
+		t.Logf("medal: %s,level: %d,intimacy: %d/%d,anchor: %s", m.MedalName, m.Level, m.Intimacy, m.NextIntimacy, m.TargetName)
+	}
+	if len(r.Items) == 0 {
+		return
+	}
+	if err = testBiliClient.LiveWearMedal(r.Items[0].MedalID); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err = testBiliClient.LiveUnwearMedal(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_LiveSign(t *testing.T) {
+	r, err := testBiliClient.LiveSign()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("%s %d/%d", r.Text, r.HadSignDays, r.AllDays)
+}
+func TestBiliClient_LiveLike(t *testing.T) {
+	if err := testBiliClient.LiveLike(23713127, 1392039, 3); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_LiveStart(t *testing.T) {
+	r, err := testBiliClient.LiveStart(23713127, 372)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("addr: %s,code: %s", r.RTMP.Addr, r.RTMP.Code)
+	if _, err = testBiliClient.LiveStop(23713127); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_LiveUpdateTitle(t *testing.T) {
+	if err := testBiliClient.LiveUpdateTitle(23713127, "biligo test"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_LiveUpdateNews(t *testing.T) {
+	if err := testBiliClient.LiveUpdateNews(23713127, "testtesttest"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_LiveAddTag(t *testing.T) {
+	if err := testBiliClient.LiveAddTag(23713127, "test"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := testBiliClient.LiveDelTag(23713127, "test"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_LiveGetAdmins(t *testing.T) {
+	r, err := testBiliClient.LiveGetAdmins(1)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, a := range r.Data {
+		t.Logf("uid: %d,uname: %s,ctime: %s", a.UID, a.Uname, a.Ctime)
+	}
+}
+func TestBiliClient_LiveMuteUser(t *testing.T) {
+	if err := testBiliClient.LiveMuteUser(23713127, 2206456, 0); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	r, err := testBiliClient.LiveGetMutedUsers(23713127, 1)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, u := range r.Data {
+		t.Logf("tuid: %d,tname: %s,ctime: %s", u.TUID, u.TName, u.Ctime)
+	}
+	if err = testBiliClient.LiveUnmuteUser(23713127, 2206456); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_LiveShieldKeyword(t *testing.T) {
+	if err := testBiliClient.LiveAddShieldKeyword(23713127, "biligo"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	keywords, err := testBiliClient.LiveGetShieldKeywords(23713127)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Log(keywords)
+	if err = testBiliClient.LiveDelShieldKeyword(23713127, "biligo"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
 func TestBiliClient_CommentSend(t *testing.T) {
 	r, err := testBiliClient.CommentSend(676583423, 1, "bil[OK]ibi[OK]litest22[OK]", 1, 0, 0)
 	if err != nil {
@@ -1010,3 +1231,84 @@ func TestBiliClient_UserGetInfo(t *testing.T) {
 	t.Logf("mid: %d,name: %s,sex: %s,level: %d,sign: %s", r.MID, r.Name, r.Sex, r.Level, r.Sign)
 	t.Logf("live: %d,officialDesc: %s,nameplateName: %s,pendantName: %s,vip: %s", r.LiveRoom.LiveStatus, r.Official.Title, r.Nameplate.Name, r.Pendant.Name, r.Vip.Label.Text)
 }
+func TestBiliClient_BangumiGetPlayURL(t *testing.T) {
+	r, err := testBiliClient.BangumiGetPlayURL(886063637, 417558227, 374717, 112, 16)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("qn: %d,duration: %d,accept: %v", r.Quality, r.TimeLength, r.AcceptDescription)
+}
+func TestBiliClient_BangumiFollow(t *testing.T) {
+	toast, err := testBiliClient.BangumiFollow(33802, true)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Log(toast)
+}
+func TestBiliClient_BangumiFollow2(t *testing.T) {
+	toast, err := testBiliClient.BangumiFollow(33802, false)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Log(toast)
+}
+func TestBiliClient_SubtitleSaveDraft(t *testing.T) {
+	s := NewSubtitle([]*SubtitleCue{{From: 0, To: 2, Location: 2, Content: "biligo"}})
+	id, err := testBiliClient.SubtitleSaveDraft(717935942, 406424024, "zh-CN", s, false)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("subtitle id: %d", id)
+	if err = testBiliClient.SubtitleDel(406424024, id); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestBiliClient_SubtitleGetMy(t *testing.T) {
+	r, err := testBiliClient.SubtitleGetMy(0, 1, 10)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("total: %d", r.Page.Total)
+	for _, s := range r.Subtitles {
+		t.Logf("%d %s %s %d %s", s.ID, s.Title, s.LanDoc, s.Status, s.RejectComment)
+	}
+}
+func TestBiliClient_LiveGetReplayList(t *testing.T) {
+	r, err := testBiliClient.LiveGetReplayList(1, 20)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, l := range r.ReplayInfo {
+		if l.LiveInfo != nil {
+			t.Logf("key: %s,title: %s", l.LiveInfo.LiveKey, l.LiveInfo.Title)
+		}
+	}
+}
+func TestBiliClient_LiveGetRecommend(t *testing.T) {
+	r, err := testBiliClient.LiveGetRecommend()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, l := range r {
+		t.Logf("room: %d,uname: %s,title: %s,area: %s", l.RoomID, l.Uname, l.Title, l.AreaV2Name)
+	}
+}
+func TestBiliClient_LiveGetFollowingLive(t *testing.T) {
+	r, err := testBiliClient.LiveGetFollowingLive(1, 10)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("count: %d", r.Count)
+	for _, l := range r.List {
+		t.Logf("room: %d,uname: %s,title: %s", l.RoomID, l.Uname, l.Title)
+	}
+}
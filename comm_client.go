@@ -1,12 +1,17 @@
 package biligo
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"github.com/golang/protobuf/proto"
 	"github.com/iyear/biligo/internal/util"
 	"github.com/iyear/biligo/proto/dm"
 	"github.com/tidwall/gjson"
+	"image"
+	_ "image/jpeg"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
@@ -280,6 +285,8 @@ func (c *CommClient) VideoGetRecommend(aid int64) ([]*VideoRecommendInfo, error)
 // VideoGetPlayURL 获取视频取流地址
 //
 // 所有参数、返回信息和取流方法的说明请直接前往：https://github.com/SocialSisterYi/bilibili-API-collect/blob/master/video/videostream_url.md
+//
+// qn 与 fnval 可以使用 NewPlayURLParam 构造，dash流可以使用 BestVideo BestAudio 挑选
 func (c *CommClient) VideoGetPlayURL(aid int64, cid int64, qn int, fnval int) (*VideoPlayURLResult, error) {
 	resp, err := c.RawParse(
 		BiliApiURL,
@@ -329,6 +336,47 @@ func (c *CommClient) VideoShot(aid int64, cid int64, index bool) (*VideoShot, er
 	return shot, nil
 }
 
+// VideoGetShotPvdata 下载并解析快照的bin格式截取时间表
+func (c *CommClient) VideoGetShotPvdata(shot *VideoShot) ([]int, error) {
+	raw, err := c.download(shot.Pvdata)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVideoShotPvdata(raw)
+}
+
+// VideoGetShotFrames 获取视频快照，并将截取时间表映射到拼版中的裁剪区域
+//
+// cid属性非必须 传入0表示1P
+//
+// 可用于进度条预览，配合 VideoShotFrames.At 使用
+func (c *CommClient) VideoGetShotFrames(aid int64, cid int64) (VideoShotFrames, error) {
+	shot, err := c.VideoShot(aid, cid, false)
+	if err != nil {
+		return nil, err
+	}
+	times, err := c.VideoGetShotPvdata(shot)
+	if err != nil {
+		return nil, err
+	}
+	return shot.Frames(times), nil
+}
+
+// VideoGetShotImage 下载并解码快照拼版
+//
+// 使用 CropVideoShotFrame 裁剪出单帧快照
+func (c *CommClient) VideoGetShotImage(link string) (image.Image, error) {
+	raw, err := c.download(link)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
 // DanmakuGetLikes 获取弹幕点赞数，一次可以获取多条弹幕
 //
 // Link:https://github.com/SocialSisterYi/bilibili-API-collect/blob/master/danmaku/action.md#%E6%9F%A5%E8%AF%A2%E5%BC%B9%E5%B9%95%E7%82%B9%E8%B5%9E%E6%95%B0
@@ -765,6 +813,15 @@ func (c *CommClient) GetUnixNow() (int64, error) {
 //
 // 获取实时弹幕(protobuf接口)
 func (c *CommClient) DanmakuGetByPb(tp int, cid int64, seg int) (*DanmakuResp, error) {
+	reply, err := c.DanmakuGetByPbReply(tp, cid, seg)
+	if err != nil {
+		return nil, err
+	}
+	return NewDanmakuResp(reply), nil
+}
+
+// DanmakuGetByPbReply 获取实时弹幕的原始protobuf结构，可用于读取未解析到 DanmakuResp 的字段
+func (c *CommClient) DanmakuGetByPbReply(tp int, cid int64, seg int) (*dm.DmSegMobileReply, error) {
 	resp, err := c.Raw(
 		BiliApiURL,
 		"x/v2/dm/web/seg.so",
@@ -778,30 +835,172 @@ func (c *CommClient) DanmakuGetByPb(tp int, cid int64, seg int) (*DanmakuResp, e
 	if err != nil {
 		return nil, err
 	}
-	var reply dm.DmSegMobileReply
-	var r = &DanmakuResp{}
-	if err := proto.Unmarshal(resp, &reply); err != nil {
+	return decodeDanmakuSeg(resp)
+}
+
+// DanmakuGetView 获取弹幕元数据(protobuf接口)
+//
+// 包含实时弹幕分段数、互动弹幕、高级弹幕专包url与弹幕设置
+//
+// aid: 稿件avid 可传入0
+//
+// 人像蒙版url不在该接口中，请使用 DanmakuGetMask 获取
+func (c *CommClient) DanmakuGetView(tp int, cid int64, aid int64) (*DanmakuView, error) {
+	resp, err := c.Raw(
+		BiliApiURL,
+		"x/v2/dm/web/view",
+		"GET",
+		map[string]string{
+			"type": strconv.Itoa(tp),
+			"oid":  strconv.FormatInt(cid, 10),
+			"pid":  util.IF(aid == 0, "", strconv.FormatInt(aid, 10)).(string),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var reply dm.DmWebViewReply
+	if err = proto.Unmarshal(resp, &reply); err != nil {
 		return nil, err
 	}
-	for _, elem := range reply.GetElems() {
-		r.Danmaku = append(r.Danmaku, &Danmaku{
-			ID:       uint64(elem.Id),
-			Progress: int64(elem.Progress),
-			Mode:     int(elem.Mode),
-			FontSize: int(elem.Fontsize),
-			Color:    int(elem.Color),
-			MidHash:  elem.MidHash,
-			Content:  elem.Content,
-			Ctime:    elem.Ctime,
-			Weight:   int(elem.Weight),
-			Action:   elem.Action,
-			Pool:     int(elem.Pool),
-			IDStr:    elem.IdStr,
-			Attr:     int(elem.Attr),
+	r := &DanmakuView{
+		State:        int(reply.GetState()),
+		Text:         reply.GetText(),
+		TextSide:     reply.GetTextSide(),
+		SegPageSize:  reply.GetDmSge().GetPageSize(),
+		SegTotal:     reply.GetDmSge().GetTotal(),
+		SpecialDms:   reply.GetSpecialDms(),
+		CheckBox:     reply.GetCheckBox(),
+		Count:        reply.GetCount(),
+		ReportFilter: reply.GetReportFilter(),
+	}
+	if f := reply.GetFlag(); f != nil {
+		r.Flag = &DanmakuViewFlag{
+			RecFlag:   int(f.RecFlag),
+			RecText:   f.RecText,
+			RecSwitch: int(f.RecSwitch),
+		}
+	}
+	for _, cmd := range reply.GetCommandDms() {
+		mid, _ := strconv.ParseInt(cmd.Mid, 10, 64)
+		r.CommandDms = append(r.CommandDms, &DanmakuCommand{
+			ID:       cmd.Id,
+			IDStr:    cmd.IdStr,
+			Oid:      cmd.Oid,
+			MID:      mid,
+			Progress: int64(cmd.Progress),
+			Ctime:    cmd.Ctime,
+			Mtime:    cmd.Mtime,
+			Content:  cmd.Content,
+			Command:  cmd.Command,
+			Extra:    cmd.Extra,
 		})
 	}
+	if st := reply.GetDmSetting(); st != nil {
+		r.Setting = &DanmakuViewSetting{
+			DmSwitch:     st.DmSwitch,
+			AISwitch:     st.AiSwitch,
+			AILevel:      int(st.AiLevel),
+			BlockTop:     st.Blocktop,
+			BlockScroll:  st.Blockscroll,
+			BlockBottom:  st.Blockbottom,
+			BlockColor:   st.Blockcolor,
+			BlockSpecial: st.Blockspecial,
+			PreventShade: st.Preventshade,
+			DMask:        st.Dmask,
+			Opacity:      st.Opacity,
+			DmArea:       int(st.Dmarea),
+			SpeedPlus:    st.Speedplus,
+			FontSize:     st.Fontsize,
+			ScreenSync:   st.Screensync,
+			SpeedSync:    st.Speedsync,
+			FontFamily:   st.Fontfamily,
+			Bold:         st.Bold,
+			FontBorder:   int(st.Fontborder),
+			DrawType:     st.DrawType,
+		}
+	}
 	return r, nil
+}
 
+// DanmakuGetMask 获取智能防挡弹幕的人像蒙版信息
+//
+// 视频没有蒙版时返回nil
+func (c *CommClient) DanmakuGetMask(aid int64, cid int64) (*DanmakuMask, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"x/player/v2",
+		"GET",
+		map[string]string{
+			"aid": strconv.FormatInt(aid, 10),
+			"cid": strconv.FormatInt(cid, 10),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	m := gjson.Get(string(resp.Data), "dm_mask")
+	if !m.Exists() || m.Type == gjson.Null {
+		return nil, nil
+	}
+	var r *DanmakuMask
+	if err = json.Unmarshal([]byte(m.Raw), &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DanmakuGetAll 获取整个视频的实时弹幕
+//
+// duration: 视频时长 单位为秒 即 VideoPage.Duration
+// 传入0时从 DanmakuGetView 获取分段数，仍然失败时将顺序获取直到遇到空分段
+//
+// parallel: 最大并发数 传入0使用默认值4
+//
+// 各分段按dmid去重，结果按出现时间排序
+func (c *CommClient) DanmakuGetAll(tp int, cid int64, duration int64, parallel int) (*DanmakuResp, error) {
+	return collectDanmakuSegs(context.Background(), func(seg int) (*DanmakuResp, error) {
+		return c.DanmakuGetByPb(tp, cid, seg)
+	}, c.danmakuSegCount(tp, cid, duration), parallel)
+}
+
+// DanmakuStream 流式获取整个视频的实时弹幕，参数同 DanmakuGetAll
+//
+// 分段按顺序写入channel，获取完毕或出错后channel关闭，出错时最后一个分段的Err不为空
+//
+// 提前停止读取时需要取消ctx，否则后台goroutine无法退出
+func (c *CommClient) DanmakuStream(ctx context.Context, tp int, cid int64, duration int64, parallel int) <-chan *DanmakuSeg {
+	return streamDanmakuSegs(ctx, func(seg int) (*DanmakuResp, error) {
+		return c.DanmakuGetByPb(tp, cid, seg)
+	}, c.danmakuSegCount(tp, cid, duration), parallel)
+}
+
+// danmakuSegCount 未知时长时从弹幕元数据获取分段数，获取失败返回0
+func (c *CommClient) danmakuSegCount(tp int, cid int64, duration int64) int {
+	if duration > 0 {
+		return DanmakuSegCount(duration)
+	}
+	view, err := c.DanmakuGetView(tp, cid, 0)
+	if err != nil {
+		return 0
+	}
+	return int(view.SegTotal)
+}
+
+// DanmakuGetXML
+//
+// 获取实时弹幕(旧版XML接口)，可作为protobuf接口的备用来源
+//
+// 只返回弹幕池中的部分弹幕，数量上限为视频的弹幕池容量
+func (c *CommClient) DanmakuGetXML(cid int64) (*DanmakuResp, error) {
+	raw, err := c.download(BiliApiURL + "x/v1/dm/list.so?oid=" + strconv.FormatInt(cid, 10))
+	if err != nil {
+		return nil, err
+	}
+	if raw, err = inflateDanmakuXML(raw); err != nil {
+		return nil, err
+	}
+	return ParseDanmakuXML(raw)
 }
 
 // DanmakuGetShot
@@ -1150,6 +1349,47 @@ func (c *CommClient) LiveGetAreaInfo() ([]*LiveAreaInfo, error) {
 	return r, nil
 }
 
+// LiveGetAreaTree 获取直播分区树，可按ID或名称查找分区
+func (c *CommClient) LiveGetAreaTree() (*LiveAreaTree, error) {
+	info, err := c.LiveGetAreaInfo()
+	if err != nil {
+		return nil, err
+	}
+	return NewLiveAreaTree(info)
+}
+
+// LiveGetAreaRoomList 获取分区下正在直播的直播间
+//
+// parentAreaID: 父分区ID
+//
+// areaID: 子分区ID 为0时获取整个父分区
+//
+// sort: 排序方式 LiveAreaSortXXX 或 LiveAreaRoomList.NewTags 中的 SortType
+//
+// pn: 页码 从1开始 每页数量由服务端决定
+func (c *CommClient) LiveGetAreaRoomList(parentAreaID int, areaID int, sort string, pn int) (*LiveAreaRoomList, error) {
+	resp, err := c.RawParse(
+		BiliLiveURL,
+		"xlive/web-interface/v1/second/getList",
+		"GET",
+		map[string]string{
+			"platform":       "web",
+			"parent_area_id": strconv.Itoa(parentAreaID),
+			"area_id":        strconv.Itoa(areaID),
+			"sort_type":      sort,
+			"page":           strconv.Itoa(pn),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveAreaRoomList{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // LiveGetGuardList 获取直播间大航海列表
 //
 // roomID: 真实直播间ID
@@ -1206,6 +1446,142 @@ func (c *CommClient) LiveGetMedalRank(roomID int64, mid int64) (*LiveMedalRank,
 	return r, nil
 }
 
+// LiveGetDanmakuHistory 获取直播间最近的弹幕
+//
+// roomID: 真实直播间ID
+func (c *CommClient) LiveGetDanmakuHistory(roomID int64) (*LiveDanmakuHistory, error) {
+	resp, err := c.RawParse(
+		BiliLiveURL,
+		"xlive/web-room/v1/dM/gethistory",
+		"GET",
+		map[string]string{
+			"roomid": strconv.FormatInt(roomID, 10),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveDanmakuHistory{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveGetSuperChatList 获取直播间正在显示的醒目留言
+//
+// roomID: 真实直播间ID
+func (c *CommClient) LiveGetSuperChatList(roomID int64) ([]*LiveSuperChat, error) {
+	resp, err := c.RawParse(
+		BiliLiveURL,
+		"av/v1/SuperChat/getMessageList",
+		"GET",
+		map[string]string{
+			"room_id": strconv.FormatInt(roomID, 10),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		List []*LiveSuperChat `json:"list"`
+	}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	for _, sc := range r.List {
+		sc.Medal = liveMedalOrNil(sc.Medal)
+	}
+	return r.List, nil
+}
+
+// LiveGetOnlineGoldRank 获取直播间高能榜 即在线观众按本场贡献排序
+//
+// roomID: 真实直播间ID
+//
+// mid: 主播mid
+//
+// pn: 页码 从1开始
+//
+// ps: 每页项数 最大50
+func (c *CommClient) LiveGetOnlineGoldRank(roomID int64, mid int64, pn int, ps int) (*LiveOnlineGoldRank, error) {
+	resp, err := c.RawParse(
+		BiliLiveURL,
+		"xlive/general-interface/v1/rank/getOnlineGoldRank",
+		"GET",
+		map[string]string{
+			"roomId":   strconv.FormatInt(roomID, 10),
+			"ruid":     strconv.FormatInt(mid, 10),
+			"page":     strconv.Itoa(pn),
+			"pageSize": strconv.Itoa(ps),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveOnlineGoldRank{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveGetFansMembers 获取主播的粉丝团成员 按亲密度排序
+//
+// mid: 主播mid
+//
+// pn: 页码 从1开始
+//
+// ps: 每页项数 最大30
+func (c *CommClient) LiveGetFansMembers(mid int64, pn int, ps int) (*LiveFansMembers, error) {
+	resp, err := c.RawParse(
+		BiliLiveURL,
+		"xlive/general-interface/v1/rank/getFansMembersRank",
+		"GET",
+		map[string]string{
+			"ruid":      strconv.FormatInt(mid, 10),
+			"page":      strconv.Itoa(pn),
+			"page_size": strconv.Itoa(ps),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveFansMembers{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveGetRecordList 获取直播间的往期直播记录
+//
+// roomID: 真实直播间ID
+//
+// pn: 页码 从1开始
+//
+// ps: 每页项数 最大20
+func (c *CommClient) LiveGetRecordList(roomID int64, pn int, ps int) (*LiveRecordList, error) {
+	resp, err := c.RawParse(
+		BiliLiveURL,
+		"xlive/web-room/v1/record/getList",
+		"GET",
+		map[string]string{
+			"room_id":   strconv.FormatInt(roomID, 10),
+			"page":      strconv.Itoa(pn),
+			"page_size": strconv.Itoa(ps),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LiveRecordList{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 // LiveGetPlayURL 获取直播流信息
 //
 // qn: 原画:10000 蓝光:400 超清:250 高清:150 流畅:80
@@ -1232,6 +1608,74 @@ func (c *CommClient) LiveGetPlayURL(roomID int64, qn int) (*LivePlayURL, error)
 	return r, nil
 }
 
+// LiveGetPlayInfo 获取直播间信息与FLV、HLS直播流
+//
+// roomID: 可为短号也可以是真实房号
+//
+// qn: 原画:10000 蓝光:400 超清:250 高清:150 流畅:80 不可用时返回较低的清晰度，见 LiveStreamCodec.CurrentQn
+func (c *CommClient) LiveGetPlayInfo(roomID int64, qn int) (*LivePlayInfo, error) {
+	resp, err := c.RawParse(
+		BiliLiveURL,
+		"xlive/web-room/v2/index/getRoomPlayInfo",
+		"GET",
+		map[string]string{
+			"room_id":  strconv.FormatInt(roomID, 10),
+			"protocol": "0,1",
+			"format":   "0,1,2",
+			"codec":    "0,1",
+			"qn":       strconv.Itoa(qn),
+			"platform": "web",
+			"ptype":    "8",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &LivePlayInfo{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LiveGetStatusByUIDs 批量获取主播的直播间状态
+//
+// 返回以mid为key的map，没有直播间的mid不在其中
+func (c *CommClient) LiveGetStatusByUIDs(uids []int64) (map[int64]*LiveRoomStatus, error) {
+	raw, err := c.raw(
+		BiliLiveURL,
+		"room/v1/Room/get_status_info_by_uids",
+		"GET",
+		nil,
+		func(d *url.Values) {
+			for _, uid := range uids {
+				d.Add("uids[]", strconv.FormatInt(uid, 10))
+			}
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	r := make(map[int64]*LiveRoomStatus)
+	// 没有结果时data为空数组
+	if !gjson.ParseBytes(resp.Data).IsObject() {
+		return r, nil
+	}
+	var m map[string]*LiveRoomStatus
+	if err = json.Unmarshal(resp.Data, &m); err != nil {
+		return nil, err
+	}
+	for _, s := range m {
+		r[s.UID] = s
+	}
+	return r, nil
+}
+
 // LiveGetAllGiftInfo 获取所有礼物信息
 //
 // areaID: 子分区ID 从 LiveGetAreaInfo 获取
@@ -1384,3 +1828,379 @@ func (c *CommClient) UserGetInfo(mid int64) (*UserInfo, error) {
 	}
 	return r, nil
 }
+
+// UserGetInfoByMidHash 由弹幕发送者hash反查用户信息
+//
+// hash: 即 Danmaku.MidHash
+//
+// 使用 CrackMidHash 得到候选mid后逐个获取信息，不存在的账号会被跳过，
+// 可能返回多个用户，全部获取失败时返回最后一个错误
+func (c *CommClient) UserGetInfoByMidHash(hash string) ([]*UserInfo, error) {
+	mids, err := CrackMidHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	var users []*UserInfo
+	for _, mid := range mids {
+		info, e := c.UserGetInfo(mid)
+		if e != nil {
+			err = e
+			continue
+		}
+		users = append(users, info)
+	}
+	if len(users) == 0 && err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// BangumiGetSeason 获取番剧、影视等PGC剧集信息
+//
+// ssid: 剧集ssid
+//
+// epid: 分集epid
+//
+// ssid 与 epid 二选一，另一个传入0
+//
+// 分集中的 AID CID 可直接用于 VideoGetPlayURL DanmakuGetByPb CommentGetMain 等接口
+func (c *CommClient) BangumiGetSeason(ssid int64, epid int64) (*BangumiSeason, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"pgc/view/web/season",
+		"GET",
+		map[string]string{
+			"season_id": util.IF(ssid == 0, "", strconv.FormatInt(ssid, 10)).(string),
+			"ep_id":     util.IF(epid == 0, "", strconv.FormatInt(epid, 10)).(string),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &BangumiSeason{}
+	if err = json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// BangumiGetMedia 从mdid获取剧集基本信息和评分
+//
+// mdid: 剧集mdid
+//
+// 返回的 SeasonID 可用于 BangumiGetSeason
+func (c *CommClient) BangumiGetMedia(mdid int64) (*BangumiMedia, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"pgc/review/user",
+		"GET",
+		map[string]string{
+			"media_id": strconv.FormatInt(mdid, 10),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r struct {
+		Media *BangumiMedia `json:"media"`
+	}
+	if err = json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, err
+	}
+	return r.Media, nil
+}
+
+// BangumiGetSection 获取剧集分集列表
+//
+// ssid: 剧集ssid
+//
+// 包含正片与预告、花絮、PV等其他分节
+func (c *CommClient) BangumiGetSection(ssid int64) (*BangumiSectionList, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"pgc/web/season/section",
+		"GET",
+		map[string]string{
+			"season_id": strconv.FormatInt(ssid, 10),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &BangumiSectionList{}
+	if err = json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// BangumiGetPlayURL 获取番剧分集取流地址
+//
+// 参数与返回值同 VideoGetPlayURL，额外需要分集epid
+//
+// 会员专享的分集请使用 BiliClient 请求
+func (c *CommClient) BangumiGetPlayURL(aid int64, cid int64, epid int64, qn int, fnval int) (*VideoPlayURLResult, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"pgc/player/web/playurl",
+		"GET",
+		map[string]string{
+			"avid":  strconv.FormatInt(aid, 10),
+			"cid":   strconv.FormatInt(cid, 10),
+			"ep_id": strconv.FormatInt(epid, 10),
+			"qn":    strconv.Itoa(qn),
+			"fnval": strconv.Itoa(fnval),
+			"fnver": "0",
+			"fourk": "1",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r *VideoPlayURLResult
+	if err = json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// BangumiGetShortReviews 获取剧集短评(用户评分)
+//
+// mdid: 剧集mdid
+//
+// cursor: 游标 第一页传入0，之后传入上一页返回的 Next
+//
+// ps: 每页项数 最大20
+//
+// sort: 排序方式 0：默认 1：最新
+func (c *CommClient) BangumiGetShortReviews(mdid int64, cursor int64, ps int, sort int) (*BangumiReviews, error) {
+	return c.bangumiGetReviews("pgc/review/short/list", mdid, cursor, ps, sort)
+}
+
+// BangumiGetLongReviews 获取剧集长评
+//
+// 参数同 BangumiGetShortReviews
+func (c *CommClient) BangumiGetLongReviews(mdid int64, cursor int64, ps int, sort int) (*BangumiReviews, error) {
+	return c.bangumiGetReviews("pgc/review/long/list", mdid, cursor, ps, sort)
+}
+func (c *CommClient) bangumiGetReviews(endpoint string, mdid int64, cursor int64, ps int, sort int) (*BangumiReviews, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		endpoint,
+		"GET",
+		map[string]string{
+			"media_id": strconv.FormatInt(mdid, 10),
+			"cursor":   util.IF(cursor == 0, "", strconv.FormatInt(cursor, 10)).(string),
+			"ps":       strconv.Itoa(ps),
+			"sort":     strconv.Itoa(sort),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &BangumiReviews{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// BangumiGetTimeline 获取番剧时间表
+//
+// tp: 类型 1：番剧 4：国创
+//
+// before: 获取今天之前的天数 区间:[0,7]
+//
+// after: 获取今天之后的天数 区间:[0,7]
+func (c *CommClient) BangumiGetTimeline(tp int, before int, after int) ([]*BangumiTimeline, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"pgc/web/timeline",
+		"GET",
+		map[string]string{
+			"types":  strconv.Itoa(tp),
+			"before": strconv.Itoa(before),
+			"after":  strconv.Itoa(after),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r []*BangumiTimeline
+	if err = json.Unmarshal(resp.Result, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SearchAll 综合搜索
+//
+// 结果中的高亮标签已去除，每类结果最多返回一页
+//
+// pn: 页码
+func (c *CommClient) SearchAll(keyword string, pn int) (*SearchAll, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"x/web-interface/search/all/v2",
+		"GET",
+		map[string]string{
+			"keyword": keyword,
+			"page":    strconv.Itoa(pn),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &SearchAll{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	r.Result = &SearchResult{ResultType: "all"}
+	for _, item := range gjson.Get(string(resp.Data), "result").Array() {
+		if err = parseSearchResult(r.Result, item.Get("result_type").String(), []byte(item.Get("data").Raw)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// SearchByType 分类搜索
+//
+// tp: 搜索类型 SearchTypeVideo SearchTypeBangumi SearchTypeFT SearchTypeLive SearchTypeLiveRoom
+// SearchTypeLiveUser SearchTypeUser SearchTypeArticle SearchTypeTopic
+//
+// opt: 筛选条件 传入nil不进行筛选
+//
+// 结果中的高亮标签已去除，结果保存在 SearchResult 对应类型的字段中
+func (c *CommClient) SearchByType(tp string, keyword string, opt *SearchOption) (*SearchType, error) {
+	if opt == nil {
+		opt = &SearchOption{}
+	}
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"x/web-interface/search/type",
+		"GET",
+		map[string]string{
+			"search_type": tp,
+			"keyword":     keyword,
+			"order":       opt.Order,
+			"order_sort":  strconv.Itoa(opt.OrderSort),
+			"duration":    strconv.Itoa(opt.Duration),
+			"tids":        strconv.Itoa(opt.TID),
+			"user_type":   strconv.Itoa(opt.UserType),
+			"category_id": strconv.Itoa(opt.CategoryID),
+			"page":        util.IF(opt.Page <= 0, "1", strconv.Itoa(opt.Page)).(string),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r = &SearchType{}
+	if err = json.Unmarshal(resp.Data, &r); err != nil {
+		return nil, err
+	}
+	r.Result = &SearchResult{ResultType: tp}
+	result := gjson.Get(string(resp.Data), "result")
+	// 直播搜索同时返回直播间与主播，result为对象
+	if result.IsObject() {
+		for _, t := range []string{SearchTypeLiveRoom, SearchTypeLiveUser} {
+			if err = parseSearchResult(r.Result, t, []byte(result.Get(t).Raw)); err != nil {
+				return nil, err
+			}
+		}
+		return r, nil
+	}
+	if err = parseSearchResult(r.Result, tp, []byte(result.Raw)); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SearchGetSuggest 获取搜索建议
+//
+// term: 输入的搜索词
+func (c *CommClient) SearchGetSuggest(term string) ([]*SearchSuggest, error) {
+	raw, err := c.Raw(
+		BiliSearchURL,
+		"main/suggest",
+		"GET",
+		map[string]string{
+			"term":         term,
+			"main_ver":     "v1",
+			"func":         "suggest",
+			"suggest_type": "accurate",
+			"sub_type":     "tag",
+			"tag_num":      "10",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	var r []*SearchSuggest
+	if err = json.Unmarshal([]byte(gjson.Get(string(resp.Result), "tag").Raw), &r); err != nil {
+		return nil, err
+	}
+	for _, s := range r {
+		s.Name, _ = ParseSearchHighlight(s.Name)
+	}
+	return r, nil
+}
+
+// SearchGetHotWords 获取热搜词列表
+func (c *CommClient) SearchGetHotWords() ([]*SearchHotWord, error) {
+	raw, err := c.Raw(BiliSearchURL, "main/hotword", "GET", map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = c.parse(raw); err != nil {
+		return nil, err
+	}
+	var r []*SearchHotWord
+	if err = json.Unmarshal([]byte(gjson.GetBytes(raw, "list").Raw), &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SearchGetTrending 获取搜索框下的热搜趋势
+//
+// limit: 获取数量 最大为50
+func (c *CommClient) SearchGetTrending(limit int) ([]*SearchHotWord, error) {
+	resp, err := c.RawParse(
+		BiliApiURL,
+		"x/web-interface/search/square",
+		"GET",
+		map[string]string{
+			"limit": strconv.Itoa(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var r []*SearchHotWord
+	if err = json.Unmarshal([]byte(gjson.Get(string(resp.Data), "trending.list").Raw), &r); err != nil {
+		return nil, err
+	}
+	for i, w := range r {
+		// 趋势列表不返回排名
+		if w.Pos == 0 {
+			w.Pos = i + 1
+		}
+	}
+	return r, nil
+}
+
+// SubtitleGet 下载并解析BCC格式字幕
+//
+// link: 字幕文件URL 即 VideoSubtitleList.SubtitleURL
+func (c *CommClient) SubtitleGet(link string) (*Subtitle, error) {
+	raw, err := c.download(link)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSubtitleBCC(raw)
+}
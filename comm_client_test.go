@@ -1,6 +1,7 @@
 package biligo
 
 import (
+	"context"
 	"testing"
 )
 
@@ -120,6 +121,27 @@ func TestCommClient_VideoShot2(t *testing.T) {
 	// index传入false 则Index属性为空
 	t.Logf("index: %v", shot.Index)
 }
+func TestCommClient_VideoGetShotFrames(t *testing.T) {
+	frames, err := testCommClient.VideoGetShotFrames(759949922, 0)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	f := frames.At(60)
+	t.Logf("frames: %d,60s: %d %s %v", len(frames), f.Time, f.Image, f.Rect)
+
+	img, err := testCommClient.VideoGetShotImage(f.Image)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	tile, err := CropVideoShotFrame(img, f)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("sheet: %v,tile: %v", img.Bounds(), tile.Bounds())
+}
 func TestCommClient_GetUnixNow(t *testing.T) {
 	unix, err := testCommClient.GetUnixNow()
 	if err != nil {
@@ -215,6 +237,60 @@ func TestCommClient_DanmakuPBGet(t *testing.T) {
 		t.Logf("content: %s,midhash: %s,progress: %d,id: %s", dm.Content, dm.MidHash, dm.Progress, dm.IDStr)
 	}
 }
+func TestCommClient_DanmakuGetView(t *testing.T) {
+	r, err := testCommClient.DanmakuGetView(1, 1176840, 810872)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("state: %d,seg: %d*%dms,count: %d,special: %v", r.State, r.SegTotal, r.SegPageSize, r.Count, r.SpecialDms)
+	for _, cmd := range r.CommandDms {
+		t.Logf("%s %d %s %s", cmd.Command, cmd.Progress, cmd.Content, cmd.Extra)
+	}
+	t.Logf("setting: %+v", r.Setting)
+}
+func TestCommClient_DanmakuGetMask(t *testing.T) {
+	r, err := testCommClient.DanmakuGetMask(759949922, 392402545)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("%+v", r)
+}
+func TestCommClient_DanmakuGetAll(t *testing.T) {
+	info, err := testCommClient.VideoGetInfo(759949922)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	page := info.Pages[0]
+	r, err := testCommClient.DanmakuGetAll(1, page.CID, page.Duration, 4)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("duration: %d,num: %d", page.Duration, len(r.Danmaku))
+}
+func TestCommClient_DanmakuStream(t *testing.T) {
+	for seg := range testCommClient.DanmakuStream(context.Background(), 1, 1176840, 0, 0) {
+		if seg.Err != nil {
+			t.Error(seg.Err)
+			t.FailNow()
+		}
+		t.Logf("seg: %d,num: %d", seg.Seg, len(seg.Danmaku))
+	}
+}
+func TestCommClient_DanmakuGetXML(t *testing.T) {
+	r, err := testCommClient.DanmakuGetXML(1176840)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("num: %d", len(r.Danmaku))
+	for _, dm := range r.Danmaku {
+		t.Logf("content: %s,midhash: %s,progress: %d,id: %s", dm.Content, dm.MidHash, dm.Progress, dm.IDStr)
+	}
+}
 func TestCommClient_DanmakuShotGet(t *testing.T) {
 	r, err := testCommClient.DanmakuGetShot(759949922)
 	if err != nil {
@@ -560,6 +636,34 @@ func TestCommClient_LiveGetPlayURL(t *testing.T) {
 		t.Logf("order: %d,url: %s", u.Order, u.URL)
 	}
 }
+func TestCommClient_LiveGetPlayInfo(t *testing.T) {
+	r, err := testCommClient.LiveGetPlayInfo(923833, 10000)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("room: %d,status: %d", r.RoomID, r.LiveStatus)
+	if r.PlayURLInfo == nil {
+		return
+	}
+	for _, s := range r.PlayURLInfo.PlayURL.Stream {
+		for _, f := range s.Format {
+			for _, c := range f.Codec {
+				t.Logf("protocol: %s,format: %s,codec: %s,qn: %d,urls: %v", s.ProtocolName, f.FormatName, c.CodecName, c.CurrentQn, c.URLs())
+			}
+		}
+	}
+}
+func TestCommClient_LiveGetStatusByUIDs(t *testing.T) {
+	r, err := testCommClient.LiveGetStatusByUIDs([]int64{672328094, 672346917, 1})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for uid, s := range r {
+		t.Logf("uid: %d,room: %d,status: %d,title: %s,area: %s", uid, s.RoomID, s.LiveStatus, s.Title, s.AreaV2Name)
+	}
+}
 func TestCommClient_LiveGetAllGiftInfo(t *testing.T) {
 	r, err := testCommClient.LiveGetAllGiftInfo(545068, 86, 2)
 	if err != nil {
@@ -646,3 +750,287 @@ func TestCommClient_UserGetInfo(t *testing.T) {
 	t.Logf("mid: %d,name: %s,sex: %s,level: %d,sign: %s", r.MID, r.Name, r.Sex, r.Level, r.Sign)
 	t.Logf("live: %d,officialDesc: %s,nameplateName: %s,pendantName: %s,vip: %s", r.LiveRoom.LiveStatus, r.Official.Title, r.Nameplate.Name, r.Pendant.Name, r.Vip.Label.Text)
 }
+func TestCommClient_UserGetInfoByMidHash(t *testing.T) {
+	r, err := testCommClient.UserGetInfoByMidHash(MidHash(2206456))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, u := range r {
+		t.Logf("mid: %d,name: %s,level: %d", u.MID, u.Name, u.Level)
+	}
+}
+func TestCommClient_BangumiGetSeason(t *testing.T) {
+	r, err := testCommClient.BangumiGetSeason(33802, 0)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("ssid: %d,mdid: %d,title: %s,total: %d,views: %d", r.SeasonID, r.MediaID, r.Title, r.Total, r.Stat.Views)
+	for _, ep := range r.Episodes {
+		t.Logf("\tepid: %d,aid: %d,cid: %d,title: %s %s", ep.ID, ep.AID, ep.CID, ep.Title, ep.LongTitle)
+	}
+}
+func TestCommClient_BangumiGetSeason2(t *testing.T) {
+	r, err := testCommClient.BangumiGetSeason(0, 374717)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("ssid: %d,title: %s,episodes: %d", r.SeasonID, r.Title, len(r.Episodes))
+}
+func TestCommClient_BangumiGetMedia(t *testing.T) {
+	r, err := testCommClient.BangumiGetMedia(28229899)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("ssid: %d,title: %s,type: %s,score: %.1f,count: %d", r.SeasonID, r.Title, r.TypeName, r.Rating.Score, r.Rating.Count)
+}
+func TestCommClient_BangumiGetSection(t *testing.T) {
+	r, err := testCommClient.BangumiGetSection(33802)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("main: %s,episodes: %d", r.MainSection.Title, len(r.MainSection.Episodes))
+	for _, s := range r.Section {
+		t.Logf("\tsection: %s,episodes: %d", s.Title, len(s.Episodes))
+	}
+}
+func TestCommClient_BangumiGetPlayURL(t *testing.T) {
+	s, err := testCommClient.BangumiGetSeason(33802, 0)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	ep := s.Episodes[0]
+	r, err := testCommClient.BangumiGetPlayURL(ep.AID, ep.CID, ep.ID, 80, 16)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("qn: %d,duration: %d", r.Quality, r.TimeLength)
+	if v := r.BestVideo(); v != nil {
+		t.Logf("id: %d,codecs: %s,baseURL: %s", v.ID, v.Codecs, v.BaseURL)
+	}
+}
+func TestCommClient_BangumiGetShortReviews(t *testing.T) {
+	r, err := testCommClient.BangumiGetShortReviews(28229899, 0, 10, 0)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("total: %d,next: %d", r.Total, r.Next)
+	for _, l := range r.List {
+		t.Logf("\tuname: %s,score: %d,content: %s", l.Author.Uname, l.Score, l.Content)
+	}
+}
+func TestCommClient_BangumiGetLongReviews(t *testing.T) {
+	r, err := testCommClient.BangumiGetLongReviews(28229899, 0, 10, 0)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("total: %d,next: %d", r.Total, r.Next)
+	for _, l := range r.List {
+		t.Logf("\tuname: %s,score: %d,title: %s", l.Author.Uname, l.Score, l.Title)
+	}
+}
+func TestCommClient_BangumiGetTimeline(t *testing.T) {
+	r, err := testCommClient.BangumiGetTimeline(1, 2, 2)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, d := range r {
+		t.Logf("date: %s,today: %d", d.Date, d.IsToday)
+		for _, ep := range d.Episodes {
+			t.Logf("\t%s %s %s", ep.PubTime, ep.Title, ep.PubIndex)
+		}
+	}
+}
+func TestCommClient_SearchAll(t *testing.T) {
+	r, err := testCommClient.SearchAll("原神", 1)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("results: %d,pages: %d,modules: %v", r.NumResults, r.NumPages, r.ShowModuleList)
+	for _, v := range r.Result.Video {
+		t.Logf("%s %s %v", v.BVID, v.Title, v.Keywords)
+	}
+	for _, m := range r.Result.Media {
+		t.Logf("%s %d %s", m.Type, m.SeasonID, m.Title)
+	}
+}
+func TestCommClient_SearchByType(t *testing.T) {
+	r, err := testCommClient.SearchByType(SearchTypeVideo, "原神", &SearchOption{Order: "click", Duration: 1, Page: 2})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("page: %d,pages: %d", r.Page, r.NumPages)
+	for _, v := range r.Result.Video {
+		t.Logf("%s %s %s %v", v.BVID, v.Title, v.Duration, v.Keywords)
+	}
+}
+func TestCommClient_SearchByType2(t *testing.T) {
+	r, err := testCommClient.SearchByType(SearchTypeLive, "原神", nil)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, l := range r.Result.LiveRoom {
+		t.Logf("room: %d %s %d", l.RoomID, l.Title, l.Online)
+	}
+	for _, l := range r.Result.LiveUser {
+		t.Logf("user: %d %s %v", l.UID, l.Uname, l.IsLive)
+	}
+}
+func TestCommClient_SearchByType3(t *testing.T) {
+	r, err := testCommClient.SearchByType(SearchTypeUser, "原神", &SearchOption{Order: "fans", UserType: 3})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, u := range r.Result.User {
+		t.Logf("%d %s %d", u.MID, u.Uname, u.Fans)
+	}
+}
+func TestCommClient_SearchGetSuggest(t *testing.T) {
+	r, err := testCommClient.SearchGetSuggest("原")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, s := range r {
+		t.Logf("%s %s", s.Value, s.Name)
+	}
+}
+func TestCommClient_SearchGetHotWords(t *testing.T) {
+	r, err := testCommClient.SearchGetHotWords()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, w := range r {
+		t.Logf("%d %s %d", w.Pos, w.Keyword, w.WordType)
+	}
+}
+func TestCommClient_SearchGetTrending(t *testing.T) {
+	r, err := testCommClient.SearchGetTrending(10)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, w := range r {
+		t.Logf("%d %s %s", w.Pos, w.Keyword, w.ShowName)
+	}
+}
+func TestCommClient_SubtitleGet(t *testing.T) {
+	info, err := testCommClient.VideoGetInfo(207511956)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, l := range info.Subtitle.List {
+		s, err := testCommClient.SubtitleGet(l.SubtitleURL)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		t.Logf("%s cues: %d", l.LanDoc, len(s.Body))
+		t.Logf("%s", s.SRT())
+	}
+}
+func TestCommClient_LiveGetDanmakuHistory(t *testing.T) {
+	r, err := testCommClient.LiveGetDanmakuHistory(21452505)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, d := range r.Room {
+		t.Logf("[%s] %s(%d): %s", d.Timeline, d.Nickname, d.UID, d.Text)
+	}
+}
+func TestCommClient_LiveGetSuperChatList(t *testing.T) {
+	r, err := testCommClient.LiveGetSuperChatList(21452505)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, sc := range r {
+		t.Logf("uid: %d,price: %d,msg: %s", sc.UID, sc.Price, sc.Message)
+	}
+}
+func TestCommClient_LiveGetOnlineGoldRank(t *testing.T) {
+	r, err := testCommClient.LiveGetOnlineGoldRank(21452505, 434334701, 1, 20)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("num: %d", r.OnlineNum)
+	for _, u := range r.OnlineRankItem {
+		t.Logf("rank: %d,uid: %d,name: %s,score: %d", u.UserRank, u.UID, u.Name, u.Score)
+	}
+}
+func TestCommClient_LiveGetFansMembers(t *testing.T) {
+	r, err := testCommClient.LiveGetFansMembers(434334701, 1, 20)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("num: %d", r.Num)
+	for _, u := range r.Item {
+		t.Logf("rank: %d,uid: %d,name: %s,score: %d", u.Rank, u.UID, u.Name, u.Score)
+	}
+}
+func TestCommClient_LiveGetRecordList(t *testing.T) {
+	r, err := testCommClient.LiveGetRecordList(21452505, 1, 20)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("count: %d", r.Count)
+	for _, l := range r.List {
+		t.Logf("rid: %s,title: %s,start: %d,online: %d", l.RID, l.Title, l.StartTimestamp, l.Online)
+	}
+}
+func TestCommClient_LiveRecordIter(t *testing.T) {
+	it := testCommClient.LiveRecordIter(21452505, 10)
+	for i := 0; i < 25 && it.Next(); i++ {
+		t.Logf("page: %d,rid: %s,title: %s", it.Page(), it.Item().RID, it.Item().Title)
+	}
+	if err := it.Err(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestCommClient_LiveGetAreaTree(t *testing.T) {
+	r, err := testCommClient.LiveGetAreaTree()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	for _, a := range r.Areas {
+		t.Logf("id: %d,name: %s,children: %d", a.ID, a.Name, len(a.Children))
+	}
+	for _, s := range r.SubByName("其他") {
+		t.Logf("id: %d,name: %s,parent: %s", s.ID, s.Name, s.ParentName)
+	}
+}
+func TestCommClient_LiveGetAreaRoomList(t *testing.T) {
+	r, err := testCommClient.LiveGetAreaRoomList(2, 86, LiveAreaSortOnline, 1)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Logf("count: %d,has_more: %d", r.Count, r.HasMore)
+	for _, tag := range r.NewTags {
+		t.Logf("tag: %s,sort: %s", tag.Name, tag.SortType)
+	}
+	for _, l := range r.List {
+		t.Logf("room: %d,uname: %s,title: %s,online: %d", l.RoomID, l.Uname, l.Title, l.Online)
+	}
+}
@@ -0,0 +1,264 @@
+package biligo
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DanmakuASSSetting 弹幕转换为ASS时的配置，传入nil或零值字段使用默认配置
+type DanmakuASSSetting struct {
+	// 视频分辨率
+	//
+	// 默认1920x1080
+	Width, Height int
+	// 滚动弹幕从右侧进入到完全离开屏幕的秒数
+	//
+	// 默认8
+	ScrollDuration float64
+	// 顶部与底部弹幕的停留秒数
+	//
+	// 默认4
+	FixedDuration float64
+	// 弹幕不透明度 区间:(0,1]
+	//
+	// 默认0.8
+	Opacity float64
+	// 字体名称
+	//
+	// 默认Microsoft YaHei
+	Font string
+	// 字号缩放比例，标准字号25乘以该值即为ASS中的字号
+	//
+	// 默认为 Height/600 即1080P下标准字号为45
+	FontScale float64
+	// 最大屏幕密度，即弹幕可以占用的屏幕高度比例 区间:(0,1]
+	//
+	// 所有轨道都被占用时，新弹幕会被丢弃而不是重叠
+	//
+	// 默认1
+	Density float64
+}
+
+// DanmakuToASS 将弹幕转换为ASS字幕，用于离线播放时的弹幕层
+//
+// 支持滚动(1 2 3)、逆向(6)、顶部(5)、底部(4)弹幕，高级弹幕、代码弹幕、BAS弹幕会被忽略
+//
+// 每条弹幕按字号占用一条或多条轨道，同一轨道上的弹幕保证不重叠，无空闲轨道时丢弃
+func DanmakuToASS(danmaku []*Danmaku, setting *DanmakuASSSetting) []byte {
+	s := newDanmakuASSSetting(setting)
+
+	// 按出现时间排序，不修改传入的切片
+	dms := make([]*Danmaku, len(danmaku))
+	copy(dms, danmaku)
+	sort.SliceStable(dms, func(i, j int) bool {
+		return dms[i].Progress < dms[j].Progress
+	})
+
+	// 轨道高度为标准字号的高度
+	laneHeight := 25 * s.FontScale
+	layout := &danmakuLayout{
+		setting: s,
+		lanes:   int(float64(s.Height) * s.Density / laneHeight),
+		height:  laneHeight,
+	}
+	layout.scroll = make([]*danmakuLane, layout.lanes)
+	layout.reverse = make([]*danmakuLane, layout.lanes)
+	layout.top = make([]*danmakuLane, layout.lanes)
+	layout.bottom = make([]*danmakuLane, layout.lanes)
+
+	alpha := uint8(math.Round(255 * (1 - s.Opacity)))
+
+	var b bytes.Buffer
+	writeASSHeader(&b, s.Width, s.Height, []string{
+		fmt.Sprintf("Style: Danmaku,%s,%d,%s,%s,%s,%s,1,0,0,0,100,100,0,0,1,%d,0,7,0,0,0,1",
+			s.Font, int(math.Round(laneHeight)),
+			assColor(0xFFFFFF, alpha), assColor(0xFFFFFF, alpha), assColor(0x000000, alpha), assColor(0x000000, alpha),
+			int(math.Max(1, math.Round(s.FontScale)))),
+	})
+	for _, d := range dms {
+		if event := layout.place(d); event != "" {
+			b.WriteString(event)
+		}
+	}
+	return b.Bytes()
+}
+
+func newDanmakuASSSetting(setting *DanmakuASSSetting) *DanmakuASSSetting {
+	s := &DanmakuASSSetting{}
+	if setting != nil {
+		*s = *setting
+	}
+	if s.Width <= 0 || s.Height <= 0 {
+		s.Width, s.Height = 1920, 1080
+	}
+	if s.ScrollDuration <= 0 {
+		s.ScrollDuration = 8
+	}
+	if s.FixedDuration <= 0 {
+		s.FixedDuration = 4
+	}
+	if s.Opacity <= 0 || s.Opacity > 1 {
+		s.Opacity = 0.8
+	}
+	if s.Font == "" {
+		s.Font = "Microsoft YaHei"
+	}
+	if s.FontScale <= 0 {
+		s.FontScale = float64(s.Height) / 600
+	}
+	if s.Density <= 0 || s.Density > 1 {
+		s.Density = 1
+	}
+	return s
+}
+
+// danmakuLane 轨道上最后一条弹幕的信息
+type danmakuLane struct {
+	start float64 // 出现时间 单位为秒
+	width float64 // 弹幕宽度
+	speed float64 // 滚动速度 固定弹幕为0
+}
+
+type danmakuLayout struct {
+	setting *DanmakuASSSetting
+	lanes   int
+	height  float64
+	scroll  []*danmakuLane
+	reverse []*danmakuLane // 逆向弹幕单独记录，与 scroll 按行互斥
+	top     []*danmakuLane
+	bottom  []*danmakuLane
+}
+
+// place 为弹幕分配轨道并生成Dialogue行，无法放置时返回空
+func (l *danmakuLayout) place(d *Danmaku) string {
+	s := l.setting
+	size := float64(d.FontSize)
+	if size <= 0 {
+		size = 25
+	}
+	size *= s.FontScale
+
+	lines := strings.Split(strings.ReplaceAll(d.Content, "\r\n", "\n"), "\n")
+	width := 0.0
+	for _, line := range lines {
+		width = math.Max(width, danmakuTextWidth(line, size))
+	}
+	// 占用的轨道数
+	n := int(math.Ceil(size * float64(len(lines)) / l.height))
+	if n < 1 {
+		n = 1
+	}
+
+	start := float64(d.Progress) / 1000
+	var (
+		lanes    []*danmakuLane
+		opposite []*danmakuLane // 反方向滚动的轨道，同一行上两者会迎面相撞
+		duration float64
+		lane     = &danmakuLane{start: start, width: width}
+	)
+	switch d.Mode {
+	case 1, 2, 3:
+		lanes, opposite, duration = l.scroll, l.reverse, s.ScrollDuration
+		lane.speed = (float64(s.Width) + width) / duration
+	case 6:
+		lanes, opposite, duration = l.reverse, l.scroll, s.ScrollDuration
+		lane.speed = (float64(s.Width) + width) / duration
+	case 5:
+		lanes, duration = l.top, s.FixedDuration
+	case 4:
+		lanes, duration = l.bottom, s.FixedDuration
+	default:
+		return ""
+	}
+
+	i := l.find(lanes, opposite, lane, n)
+	if i < 0 {
+		return ""
+	}
+	for j := i; j < i+n; j++ {
+		lanes[j] = lane
+	}
+
+	w, h := float64(s.Width), float64(s.Height)
+	var pos string
+	switch d.Mode {
+	case 5:
+		pos = fmt.Sprintf("\\an8\\pos(%d,%d)", int(w/2), int(float64(i)*l.height))
+	case 4:
+		pos = fmt.Sprintf("\\an2\\pos(%d,%d)", int(w/2), int(h-float64(i)*l.height))
+	case 6:
+		pos = fmt.Sprintf("\\move(%d,%d,%d,%d)", int(-width), int(float64(i)*l.height), int(w), int(float64(i)*l.height))
+	default:
+		pos = fmt.Sprintf("\\move(%d,%d,%d,%d)", int(w), int(float64(i)*l.height), int(-width), int(float64(i)*l.height))
+	}
+
+	style := pos
+	if int(size) != int(25*s.FontScale) {
+		style += fmt.Sprintf("\\fs%d", int(math.Round(size)))
+	}
+	if c := uint32(d.Color) & 0xFFFFFF; c != 0xFFFFFF {
+		style += fmt.Sprintf("\\c&H%02X%02X%02X&", c&0xFF, c>>8&0xFF, c>>16&0xFF)
+		// 黑色弹幕使用白色描边，否则无法看清
+		if c == 0x000000 {
+			style += "\\3c&HFFFFFF&"
+		}
+	}
+	return fmt.Sprintf("Dialogue: 2,%s,%s,Danmaku,,0,0,0,,{%s}%s\n",
+		assTime(start), assTime(start+duration), style, assEscape(d.Content))
+}
+
+// find 找到能连续放下n条轨道的最小下标，没有时返回-1
+//
+// opposite 为反方向滚动的轨道，其上的弹幕离开屏幕前该行不可用，固定弹幕传nil
+func (l *danmakuLayout) find(lanes, opposite []*danmakuLane, d *danmakuLane, n int) int {
+	for i := 0; i+n <= len(lanes); i++ {
+		ok := true
+		for j := i; j < i+n; j++ {
+			if !l.free(lanes[j], d) || opposite != nil && opposite[j] != nil && d.start < opposite[j].start+l.setting.ScrollDuration {
+				ok = false
+				// 从冲突轨道的下一条继续
+				i = j
+				break
+			}
+		}
+		if ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// free 判断新弹幕d能否放在最后一条弹幕为last的轨道上
+func (l *danmakuLayout) free(last *danmakuLane, d *danmakuLane) bool {
+	if last == nil {
+		return true
+	}
+	// 固定弹幕 上一条消失后才能出现
+	if d.speed == 0 {
+		return d.start >= last.start+l.setting.FixedDuration
+	}
+	elapsed := d.start - last.start
+	// 上一条的尾部已经完全进入屏幕
+	if last.speed*elapsed < last.width {
+		return false
+	}
+	// 上一条离开屏幕前，新弹幕不会追上它
+	return d.speed*(l.setting.ScrollDuration-elapsed) <= float64(l.setting.Width)
+}
+
+// danmakuTextWidth 估算文本宽度，全角字符按一个字号计算，半角字符按半个字号计算
+func danmakuTextWidth(s string, size float64) float64 {
+	w := 0.0
+	for _, r := range s {
+		if r < utf8.RuneSelf || r >= 0xFF61 && r <= 0xFFDC {
+			w += size / 2
+		} else {
+			w += size
+		}
+	}
+	return w
+}
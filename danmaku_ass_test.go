@@ -0,0 +1,89 @@
+package biligo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDanmakuToASS(t *testing.T) {
+	dms := []*Danmaku{
+		{Progress: 300, Mode: 1, FontSize: 25, Color: 0xFFFFFF, Content: "第二条"},
+		{Progress: 0, Mode: 1, FontSize: 25, Color: 0xFFFFFF, Content: "第一条"},
+		{Progress: 0, Mode: 5, FontSize: 25, Color: 0xFF0000, Content: "顶部"},
+		{Progress: 0, Mode: 4, FontSize: 36, Color: 0x000000, Content: "底部"},
+		{Progress: 0, Mode: 7, FontSize: 25, Content: `[0,0,"1-1",4,"高级"]`},
+	}
+	b := string(DanmakuToASS(dms, nil))
+	t.Log(b)
+
+	lines := strings.Split(strings.TrimSpace(b[strings.Index(b, "Dialogue:"):]), "\n")
+	if len(lines) != 4 {
+		t.Errorf("dialogues: %d", len(lines))
+		t.FailNow()
+	}
+	// 排序后第一条滚动弹幕在第0轨道，0.3秒时第一条尚未完全进入屏幕，第二条放在第1轨道
+	if !strings.Contains(lines[0], `\move(1920,0,-135,0)}第一条`) || !strings.Contains(lines[3], `\move(1920,45,-135,45)}第二条`) {
+		t.Errorf("%s\n%s", lines[0], lines[3])
+		t.FailNow()
+	}
+	if !strings.Contains(lines[1], `{\an8\pos(960,0)\c&H0000FF&}顶部`) {
+		t.Error(lines[1])
+		t.FailNow()
+	}
+	if !strings.Contains(lines[2], `{\an2\pos(960,1080)\fs65\c&H000000&\3c&HFFFFFF&}底部`) {
+		t.Error(lines[2])
+		t.FailNow()
+	}
+}
+func TestDanmakuToASS2(t *testing.T) {
+	// 只有两条轨道，固定弹幕在消失前不能复用轨道
+	var dms []*Danmaku
+	for i := 0; i < 4; i++ {
+		dms = append(dms, &Danmaku{Progress: int64(i * 1000), Mode: 5, FontSize: 25, Color: 0xFFFFFF, Content: "顶部"})
+	}
+	dms = append(dms, &Danmaku{Progress: 4000, Mode: 5, FontSize: 25, Color: 0xFFFFFF, Content: "复用"})
+	b := string(DanmakuToASS(dms, &DanmakuASSSetting{Width: 640, Height: 100, FontScale: 2, Density: 1}))
+	if n := strings.Count(b, "Dialogue:"); n != 3 {
+		t.Errorf("dialogues: %d\n%s", n, b)
+		t.FailNow()
+	}
+	if !strings.Contains(b, `0:00:04.00,0:00:08.00,Danmaku,,0,0,0,,{\an8\pos(320,0)}复用`) {
+		t.Error(b)
+		t.FailNow()
+	}
+}
+func TestDanmakuToASS3(t *testing.T) {
+	// 同时出现的普通滚动与逆向弹幕不能在同一轨道迎面相撞
+	dms := []*Danmaku{
+		{Progress: 0, Mode: 1, FontSize: 25, Color: 0xFFFFFF, Content: "向左"},
+		{Progress: 0, Mode: 6, FontSize: 25, Color: 0xFFFFFF, Content: "向右"},
+	}
+	b := string(DanmakuToASS(dms, &DanmakuASSSetting{Width: 640, Height: 100, FontScale: 2, Density: 1}))
+	if !strings.Contains(b, `{\move(640,0,-100,0)}向左`) || !strings.Contains(b, `{\move(-100,50,640,50)}向右`) {
+		t.Error(b)
+		t.FailNow()
+	}
+
+	// 只有一条轨道时，逆向弹幕要等普通弹幕离开屏幕
+	dms = append(dms, &Danmaku{Progress: 8000, Mode: 6, FontSize: 25, Color: 0xFFFFFF, Content: "复用"})
+	b = string(DanmakuToASS(dms, &DanmakuASSSetting{Width: 640, Height: 50, FontScale: 2, Density: 1}))
+	if n := strings.Count(b, "Dialogue:"); n != 2 || strings.Contains(b, "向右") || !strings.Contains(b, `{\move(-100,0,640,0)}复用`) {
+		t.Error(b)
+		t.FailNow()
+	}
+}
+func TestDanmakuLayout_free(t *testing.T) {
+	l := &danmakuLayout{setting: newDanmakuASSSetting(nil)}
+	last := &danmakuLane{start: 0, width: 200, speed: (1920 + 200) / 8.0}
+	// 尾部未进入屏幕
+	if l.free(last, &danmakuLane{start: 0.5, width: 200, speed: last.speed}) {
+		t.FailNow()
+	}
+	if !l.free(last, &danmakuLane{start: 1, width: 200, speed: last.speed}) {
+		t.FailNow()
+	}
+	// 更长的弹幕速度更快，会追上上一条
+	if l.free(last, &danmakuLane{start: 1, width: 2000, speed: (1920 + 2000) / 8.0}) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,274 @@
+package biligo
+
+import (
+	"encoding/json"
+	"github.com/iyear/biligo/proto/dm"
+	"github.com/pkg/errors"
+	"regexp"
+	"strings"
+)
+
+// DanmakuRule 弹幕屏蔽规则，返回true表示屏蔽该弹幕
+//
+// 规则就是普通函数，可以自行实现，也可以用 DanmakuRuleAnd DanmakuRuleNot 组合
+type DanmakuRule func(d *Danmaku) bool
+
+// DanmakuFilter 本地弹幕过滤器，命中任一规则的弹幕将被屏蔽
+type DanmakuFilter struct {
+	rules []DanmakuRule
+}
+
+// NewDanmakuFilter 创建弹幕过滤器
+func NewDanmakuFilter(rules ...DanmakuRule) *DanmakuFilter {
+	return &DanmakuFilter{rules: rules}
+}
+
+// Add 添加规则
+func (f *DanmakuFilter) Add(rules ...DanmakuRule) *DanmakuFilter {
+	f.rules = append(f.rules, rules...)
+	return f
+}
+
+// Blocked 是否屏蔽该弹幕
+func (f *DanmakuFilter) Blocked(d *Danmaku) bool {
+	for _, rule := range f.rules {
+		if rule(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter 返回未被屏蔽的弹幕，不修改传入的切片
+func (f *DanmakuFilter) Filter(dms []*Danmaku) []*Danmaku {
+	r := make([]*Danmaku, 0, len(dms))
+	for _, d := range dms {
+		if !f.Blocked(d) {
+			r = append(r, d)
+		}
+	}
+	return r
+}
+
+// Apply 过滤 DanmakuResp 中的弹幕，返回同一个 DanmakuResp
+func (f *DanmakuFilter) Apply(r *DanmakuResp) *DanmakuResp {
+	r.Danmaku = f.Filter(r.Danmaku)
+	return r
+}
+
+// DanmakuRuleKeyword 屏蔽包含任一关键词的弹幕，不区分大小写
+func DanmakuRuleKeyword(keywords ...string) DanmakuRule {
+	lower := make([]string, 0, len(keywords))
+	for _, k := range keywords {
+		if k != "" {
+			lower = append(lower, strings.ToLower(k))
+		}
+	}
+	return func(d *Danmaku) bool {
+		content := strings.ToLower(d.Content)
+		for _, k := range lower {
+			if strings.Contains(content, k) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DanmakuRuleRegexp 屏蔽匹配任一正则的弹幕
+func DanmakuRuleRegexp(exps ...*regexp.Regexp) DanmakuRule {
+	return func(d *Danmaku) bool {
+		for _, exp := range exps {
+			if exp.MatchString(d.Content) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DanmakuRuleMidHash 屏蔽指定发送者的弹幕
+//
+// hash: 即 Danmaku.MidHash，可以用 MidHash 由mid计算
+func DanmakuRuleMidHash(hashes ...string) DanmakuRule {
+	m := make(map[string]struct{}, len(hashes))
+	for _, h := range hashes {
+		m[strings.ToLower(h)] = struct{}{}
+	}
+	return func(d *Danmaku) bool {
+		_, ok := m[d.MidHash]
+		return ok
+	}
+}
+
+// DanmakuRuleMode 屏蔽指定类型的弹幕，类型见 Danmaku.Mode
+func DanmakuRuleMode(modes ...int) DanmakuRule {
+	return danmakuRuleInts(func(d *Danmaku) int { return d.Mode }, modes)
+}
+
+// DanmakuRulePool 屏蔽指定弹幕池的弹幕，弹幕池见 Danmaku.Pool
+func DanmakuRulePool(pools ...int) DanmakuRule {
+	return danmakuRuleInts(func(d *Danmaku) int { return d.Pool }, pools)
+}
+
+func danmakuRuleInts(field func(d *Danmaku) int, values []int) DanmakuRule {
+	m := make(map[int]struct{}, len(values))
+	for _, v := range values {
+		m[v] = struct{}{}
+	}
+	return func(d *Danmaku) bool {
+		_, ok := m[field(d)]
+		return ok
+	}
+}
+
+// DanmakuRuleColorful 屏蔽非白色的彩色弹幕
+func DanmakuRuleColorful() DanmakuRule {
+	return func(d *Danmaku) bool {
+		return d.Color&0xffffff != 0xffffff
+	}
+}
+
+// DanmakuRuleWeight 屏蔽权重低于level的弹幕，与播放器中的屏蔽等级一致
+//
+// level: 区间：[1-10]
+func DanmakuRuleWeight(level int) DanmakuRule {
+	return func(d *Danmaku) bool {
+		return d.Weight < level
+	}
+}
+
+// DanmakuRuleAIScore 屏蔽云屏蔽评分 Danmaku.AIScore 不低于level的弹幕，未评分的弹幕不会被屏蔽
+func DanmakuRuleAIScore(level uint32) DanmakuRule {
+	return func(d *Danmaku) bool {
+		return d.AIScore > 0 && d.AIScore >= level
+	}
+}
+
+// DanmakuRuleAIFlag 屏蔽云屏蔽评分不低于level的弹幕
+//
+// flag: 即 dm.DmSegMobileReply 中的 AiFlag，未评分的弹幕不会被屏蔽。
+// 使用 NewDanmakuResp 转换的弹幕已带有评分，可以直接使用 DanmakuRuleAIScore
+func DanmakuRuleAIFlag(flag *dm.DanmakuAIFlag, level uint32) DanmakuRule {
+	scores := make(map[uint64]uint32, len(flag.GetDmFlags()))
+	for _, f := range flag.GetDmFlags() {
+		scores[uint64(f.GetDmid())] = f.GetFlag()
+	}
+	return func(d *Danmaku) bool {
+		s, ok := scores[d.ID]
+		return ok && s >= level
+	}
+}
+
+// DanmakuRuleConfig 按播放器的弹幕设置屏蔽，使用其中的类型屏蔽与彩色屏蔽
+func DanmakuRuleConfig(conf *DanmakuConfig) DanmakuRule {
+	var modes []int
+	if conf.BlockScroll {
+		modes = append(modes, 1, 2, 3, 6)
+	}
+	if conf.BlockBottom {
+		modes = append(modes, 4)
+	}
+	if conf.BlockTop {
+		modes = append(modes, 5)
+	}
+	if conf.BlockSpecial {
+		modes = append(modes, 7, 8, 9)
+	}
+	rules := []DanmakuRule{DanmakuRuleMode(modes...)}
+	if conf.BlockColor {
+		rules = append(rules, DanmakuRuleColorful())
+	}
+	return DanmakuRuleOr(rules...)
+}
+
+// DanmakuRuleAnd 所有规则都命中时屏蔽
+func DanmakuRuleAnd(rules ...DanmakuRule) DanmakuRule {
+	return func(d *Danmaku) bool {
+		for _, rule := range rules {
+			if !rule(d) {
+				return false
+			}
+		}
+		return len(rules) > 0
+	}
+}
+
+// DanmakuRuleOr 任一规则命中时屏蔽
+func DanmakuRuleOr(rules ...DanmakuRule) DanmakuRule {
+	return func(d *Danmaku) bool {
+		for _, rule := range rules {
+			if rule(d) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// DanmakuRuleNot 规则未命中时屏蔽，可用于白名单
+func DanmakuRuleNot(rule DanmakuRule) DanmakuRule {
+	return func(d *Danmaku) bool {
+		return !rule(d)
+	}
+}
+
+// 屏蔽列表中的规则类型
+const (
+	danmakuBlockKeyword = 0
+	danmakuBlockRegexp  = 1
+	danmakuBlockUser    = 2
+)
+
+type danmakuBlockItem struct {
+	Type   int    `json:"type"`   // 0:关键词 1:正则 2:用户
+	Filter string `json:"filter"` // 规则内容
+	Opened *bool  `json:"opened"` // 是否启用 缺省时视为启用
+}
+
+// ParseDanmakuBlockList 解析网页播放器导出的屏蔽列表JSON
+//
+// 支持播放器导出的规则数组，也支持云同步接口返回的 {"rule":[...]} 格式。
+// 正则规则可以带有 /.../ 包裹；用户规则为 Danmaku.MidHash；未启用的规则会被忽略
+func ParseDanmakuBlockList(data []byte) (DanmakuRule, error) {
+	var items []*danmakuBlockItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		var wrap struct {
+			Rule []*danmakuBlockItem `json:"rule"`
+		}
+		if e := json.Unmarshal(data, &wrap); e != nil {
+			return nil, errors.Wrap(err, "invalid block list")
+		}
+		items = wrap.Rule
+	}
+
+	var (
+		keywords []string
+		exps     []*regexp.Regexp
+		hashes   []string
+	)
+	for _, item := range items {
+		if item.Opened != nil && !*item.Opened || item.Filter == "" {
+			continue
+		}
+		switch item.Type {
+		case danmakuBlockKeyword:
+			keywords = append(keywords, item.Filter)
+		case danmakuBlockRegexp:
+			exp := item.Filter
+			if len(exp) >= 2 && strings.HasPrefix(exp, "/") && strings.HasSuffix(exp, "/") {
+				exp = exp[1 : len(exp)-1]
+			}
+			re, err := regexp.Compile(exp)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid regexp: %s", item.Filter)
+			}
+			exps = append(exps, re)
+		case danmakuBlockUser:
+			hashes = append(hashes, item.Filter)
+		default:
+			return nil, errors.Errorf("unknown block type: %d", item.Type)
+		}
+	}
+	return DanmakuRuleOr(DanmakuRuleKeyword(keywords...), DanmakuRuleRegexp(exps...), DanmakuRuleMidHash(hashes...)), nil
+}
@@ -0,0 +1,83 @@
+package biligo
+
+import (
+	"github.com/iyear/biligo/proto/dm"
+	"regexp"
+	"testing"
+)
+
+func newTestFilterDanmaku() []*Danmaku {
+	return []*Danmaku{
+		{ID: 1, Mode: 1, Color: 0xffffff, Weight: 10, Pool: 0, MidHash: "aaaa", Content: "前排", AIScore: 8},
+		{ID: 2, Mode: 5, Color: 0xffffff, Weight: 10, Pool: 0, MidHash: "bbbb", Content: "AWSL"},
+		{ID: 3, Mode: 1, Color: 0xff0000, Weight: 3, Pool: 0, MidHash: "cccc", Content: "2333"},
+		{ID: 4, Mode: 7, Color: 0xffffff, Weight: 10, Pool: 1, MidHash: "dddd", Content: "字幕"},
+	}
+}
+func danmakuIDs(dms []*Danmaku) []uint64 {
+	ids := make([]uint64, 0, len(dms))
+	for _, d := range dms {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+func TestDanmakuFilter(t *testing.T) {
+	flag := &dm.DanmakuAIFlag{DmFlags: []*dm.DanmakuFlag{{Dmid: 1, Flag: 8}, {Dmid: 2, Flag: 2}}}
+	tests := []struct {
+		name string
+		rule DanmakuRule
+		want []uint64
+	}{
+		{"keyword", DanmakuRuleKeyword("awsl"), []uint64{1, 3, 4}},
+		{"regexp", DanmakuRuleRegexp(regexp.MustCompile(`^2+3+$`)), []uint64{1, 2, 4}},
+		{"midhash", DanmakuRuleMidHash("AAAA", "dddd"), []uint64{2, 3}},
+		{"mode", DanmakuRuleMode(5), []uint64{1, 3, 4}},
+		{"pool", DanmakuRulePool(1), []uint64{1, 2, 3}},
+		{"colorful", DanmakuRuleColorful(), []uint64{1, 2, 4}},
+		{"weight", DanmakuRuleWeight(5), []uint64{1, 2, 4}},
+		{"aiflag", DanmakuRuleAIFlag(flag, 5), []uint64{2, 3, 4}},
+		{"aiscore", DanmakuRuleAIScore(5), []uint64{2, 3, 4}},
+		{"config", DanmakuRuleConfig(&DanmakuConfig{BlockTop: true, BlockSpecial: true}), []uint64{1, 3}},
+		{"and", DanmakuRuleAnd(DanmakuRuleMode(1), DanmakuRuleWeight(5)), []uint64{1, 2, 4}},
+		{"not", DanmakuRuleNot(DanmakuRulePool(1)), []uint64{4}},
+	}
+	for _, tt := range tests {
+		got := danmakuIDs(NewDanmakuFilter(tt.rule).Filter(newTestFilterDanmaku()))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: want %v,got %v", tt.name, tt.want, got)
+			t.FailNow()
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: want %v,got %v", tt.name, tt.want, got)
+				t.FailNow()
+			}
+		}
+	}
+
+	r := NewDanmakuFilter(DanmakuRuleMode(5)).Add(DanmakuRulePool(1)).Apply(&DanmakuResp{Danmaku: newTestFilterDanmaku()})
+	if got := danmakuIDs(r.Danmaku); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("apply: %v", got)
+		t.FailNow()
+	}
+}
+func TestParseDanmakuBlockList(t *testing.T) {
+	for _, data := range []string{
+		`[{"type":0,"filter":"awsl","opened":true},{"type":1,"filter":"/^2+3+$/","opened":true},{"type":2,"filter":"dddd","opened":true},{"type":0,"filter":"前排","opened":false}]`,
+		`{"rule":[{"id":1,"type":0,"filter":"awsl"},{"id":2,"type":1,"filter":"^2+3+$"},{"id":3,"type":2,"filter":"dddd"}]}`,
+	} {
+		rule, err := ParseDanmakuBlockList([]byte(data))
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if got := danmakuIDs(NewDanmakuFilter(rule).Filter(newTestFilterDanmaku())); len(got) != 1 || got[0] != 1 {
+			t.Errorf("%s: %v", data, got)
+			t.FailNow()
+		}
+	}
+	if _, err := ParseDanmakuBlockList([]byte(`[{"type":1,"filter":"("}]`)); err == nil {
+		t.Error("invalid regexp should fail")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,266 @@
+package biligo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// DanmakuHistorySetting 爬取历史弹幕时的配置，传入nil使用默认配置
+type DanmakuHistorySetting struct {
+	// 两次请求之间的最小间隔，过快会触发风控
+	//
+	// 默认1s
+	Interval time.Duration
+	// 断点文件路径，每爬取完一天保存一次，再次爬取同一cid时从断点继续
+	//
+	// 已爬取的弹幕追加写入同目录下的 Checkpoint+".danmaku"，断点文件属于其他cid时返回错误
+	//
+	// 默认不保存
+	Checkpoint string
+	// 是否合并实时弹幕
+	//
+	// 默认false
+	WithLive bool
+	// 每爬取完一天后的回调，可用于显示进度
+	//
+	// date: 日期 YYYY-MM-DD total: 当前去重后的弹幕总数
+	OnDate func(date string, total int)
+}
+
+// danmakuHistoryCheckpoint 断点文件内容
+//
+// 弹幕单独追加写入日志文件，每行一条，避免每天都重写全部弹幕
+type danmakuHistoryCheckpoint struct {
+	CID     int64               `json:"cid"`
+	Months  map[string][]string `json:"months"` // 已获取的历史弹幕日期 YYYY-MM -> [YYYY-MM-DD]
+	Dates   map[string]bool     `json:"dates"`  // 已爬取完成的日期
+	Danmaku []*Danmaku          `json:"-"`      // 已爬取的弹幕 来自日志文件
+}
+
+// 历史弹幕的日期以东八区为准
+var danmakuHistoryZone = time.FixedZone("CST", 8*3600)
+
+// DanmakuCrawlHistory 爬取视频从发布至今的全部历史弹幕
+//
+// 从 VideoInfo.Pubdate 所在月份开始逐月获取有历史弹幕的日期，再逐日获取弹幕，结果按dmid去重后按出现时间排序
+//
+// 当月与当天的数据仍会变化，不会记为已完成，从断点继续时会重新获取
+//
+// 视频历史较长时请求数量很多，建议设置断点文件
+func (b *BiliClient) DanmakuCrawlHistory(aid int64, cid int64, setting *DanmakuHistorySetting) (*DanmakuResp, error) {
+	if setting == nil {
+		setting = &DanmakuHistorySetting{}
+	}
+	interval := setting.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	info, err := b.VideoGetInfo(aid)
+	if err != nil {
+		return nil, err
+	}
+	var duration int64
+	for _, p := range info.Pages {
+		if p.CID == cid {
+			duration = p.Duration
+		}
+	}
+
+	cp, err := loadDanmakuHistoryCheckpoint(setting.Checkpoint, cid)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uint64]struct{}, len(cp.Danmaku))
+	cp.Danmaku = appendDanmakuUnique(nil, seen, cp.Danmaku)
+
+	var last time.Time
+	wait := func() {
+		if d := interval - time.Since(last); !last.IsZero() && d > 0 {
+			time.Sleep(d)
+		}
+		last = time.Now()
+	}
+
+	now := time.Now().In(danmakuHistoryZone)
+	today := now.Format("2006-01-02")
+	thisMonth := now.Format("2006-01")
+	pub := time.Unix(info.Pubdate, 0).In(danmakuHistoryZone)
+
+	for m := time.Date(pub.Year(), pub.Month(), 1, 0, 0, 0, 0, danmakuHistoryZone); !m.After(now); m = m.AddDate(0, 1, 0) {
+		month := m.Format("2006-01")
+		dates, ok := cp.Months[month]
+		if !ok || month == thisMonth {
+			wait()
+			if dates, err = b.DanmakuGetHistoryIndex(cid, m.Year(), int(m.Month())); err != nil {
+				return nil, errors.Wrapf(err, "get history index of %s", month)
+			}
+			if month != thisMonth {
+				cp.Months[month] = dates
+			}
+		}
+
+		for _, date := range dates {
+			if cp.Dates[date] {
+				continue
+			}
+			wait()
+			r, err := b.DanmakuGetHistory(cid, date)
+			if err != nil {
+				return nil, errors.Wrapf(err, "get history of %s", date)
+			}
+			n := len(cp.Danmaku)
+			cp.Danmaku = appendDanmakuUnique(cp.Danmaku, seen, r.Danmaku)
+			// 先写弹幕再记录日期，中断时最多重新爬取这一天
+			if err = cp.appendLog(setting.Checkpoint, cp.Danmaku[n:]); err != nil {
+				return nil, err
+			}
+			if date != today {
+				cp.Dates[date] = true
+			}
+			if err = cp.save(setting.Checkpoint); err != nil {
+				return nil, err
+			}
+			if setting.OnDate != nil {
+				setting.OnDate(date, len(cp.Danmaku))
+			}
+		}
+	}
+
+	result := &DanmakuResp{Danmaku: cp.Danmaku}
+	if setting.WithLive {
+		// 与历史弹幕共用请求间隔，不并发
+		live, err := collectDanmakuSegs(context.Background(), func(seg int) (*DanmakuResp, error) {
+			wait()
+			return b.DanmakuGetByPb(1, cid, seg)
+		}, DanmakuSegCount(duration), 1)
+		if err != nil {
+			return nil, errors.Wrap(err, "get live danmaku")
+		}
+		// 实时弹幕不写入断点，避免下次继续时读到过期数据
+		result.Danmaku = appendDanmakuUnique(append([]*Danmaku(nil), cp.Danmaku...), seen, live.Danmaku)
+	}
+	sortDanmaku(result.Danmaku)
+	return result, nil
+}
+
+// loadDanmakuHistoryCheckpoint 读取断点与弹幕日志，文件不存在时返回新断点，cid不同时返回错误
+func loadDanmakuHistoryCheckpoint(path string, cid int64) (*danmakuHistoryCheckpoint, error) {
+	cp := &danmakuHistoryCheckpoint{
+		CID:    cid,
+		Months: make(map[string][]string),
+		Dates:  make(map[string]bool),
+	}
+	if path == "" {
+		return cp, nil
+	}
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		// 没有断点时日志中的弹幕无法对应，从头开始
+		if err = os.Remove(danmakuHistoryLogPath(path)); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var saved danmakuHistoryCheckpoint
+	if err = json.Unmarshal(raw, &saved); err != nil {
+		return nil, errors.Wrap(err, "invalid checkpoint")
+	}
+	if saved.CID != cid {
+		return nil, errors.Errorf("checkpoint %s belongs to cid %d", path, saved.CID)
+	}
+	if saved.Months != nil {
+		cp.Months = saved.Months
+	}
+	if saved.Dates != nil {
+		cp.Dates = saved.Dates
+	}
+	if cp.Danmaku, err = loadDanmakuHistoryLog(danmakuHistoryLogPath(path)); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+func danmakuHistoryLogPath(path string) string {
+	return path + ".danmaku"
+}
+
+// loadDanmakuHistoryLog 读取弹幕日志，末尾写入不完整的一行会被截断
+func loadDanmakuHistoryLog(path string) ([]*Danmaku, error) {
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var (
+		dms   []*Danmaku
+		valid int
+	)
+	for valid < len(raw) {
+		end := bytes.IndexByte(raw[valid:], '\n')
+		if end < 0 {
+			break
+		}
+		d := &Danmaku{}
+		if err = json.Unmarshal(raw[valid:valid+end], d); err != nil {
+			break
+		}
+		dms = append(dms, d)
+		valid += end + 1
+	}
+	if valid < len(raw) {
+		if err = os.Truncate(path, int64(valid)); err != nil {
+			return nil, err
+		}
+	}
+	return dms, nil
+}
+
+// appendLog 将新弹幕追加到日志
+func (cp *danmakuHistoryCheckpoint) appendLog(path string, dms []*Danmaku) error {
+	if path == "" || len(dms) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, d := range dms {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(danmakuHistoryLogPath(path), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// save 只保存日期进度，先写入临时文件再重命名，避免中断时损坏断点
+func (cp *danmakuHistoryCheckpoint) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	raw, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err = ioutil.WriteFile(tmp, raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,79 @@
+package biligo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDanmakuHistoryCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "biligo")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	// 文件不存在时返回新断点
+	cp, err := loadDanmakuHistoryCheckpoint(path, 1176840)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	cp.Months["2021-07"] = []string{"2021-07-01", "2021-07-02"}
+	cp.Dates["2021-07-01"] = true
+	for i, c := range []string{"断点", "追加"} {
+		cp.Danmaku = append(cp.Danmaku, &Danmaku{ID: uint64(i + 1), Content: c})
+		if err = cp.appendLog(path, cp.Danmaku[i:]); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+	if err = cp.save(path); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// 断点文件中不包含弹幕
+	raw, _ := ioutil.ReadFile(path)
+	if strings.Contains(string(raw), "断点") {
+		t.Errorf("%s", raw)
+		t.FailNow()
+	}
+	// 模拟写入弹幕时中断
+	f, _ := os.OpenFile(path+".danmaku", os.O_WRONLY|os.O_APPEND, 0644)
+	_, _ = f.WriteString(`{"id":3,"cont`)
+	f.Close()
+
+	loaded, err := loadDanmakuHistoryCheckpoint(path, 1176840)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(loaded.Months["2021-07"]) != 2 || !loaded.Dates["2021-07-01"] || loaded.Dates["2021-07-02"] ||
+		len(loaded.Danmaku) != 2 || loaded.Danmaku[0].Content != "断点" || loaded.Danmaku[1].Content != "追加" {
+		t.Errorf("%+v", loaded)
+		t.FailNow()
+	}
+	// 不完整的一行被截断，之后可以继续追加
+	if err = loaded.appendLog(path, []*Danmaku{{ID: 3, Content: "继续"}}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if loaded, err = loadDanmakuHistoryCheckpoint(path, 1176840); err != nil || len(loaded.Danmaku) != 3 {
+		t.Errorf("%v %+v", err, loaded)
+		t.FailNow()
+	}
+
+	// cid不同时返回错误，且不影响原断点
+	if _, err = loadDanmakuHistoryCheckpoint(path, 1); err == nil {
+		t.Error("want error when cid differs")
+		t.FailNow()
+	}
+	if loaded, err = loadDanmakuHistoryCheckpoint(path, 1176840); err != nil || len(loaded.Danmaku) != 3 {
+		t.Errorf("%v %+v", err, loaded)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,233 @@
+package biligo
+
+import (
+	"context"
+	"github.com/golang/protobuf/proto"
+	"github.com/iyear/biligo/proto/dm"
+	"github.com/pkg/errors"
+	"sort"
+	"sync"
+)
+
+// 实时弹幕每个分段的时长 单位为秒
+const danmakuSegDuration = 360
+
+// 默认的分段并发数
+const danmakuSegParallel = 4
+
+// DanmakuSeg 流式获取弹幕时的一个分段
+type DanmakuSeg struct {
+	Seg     int        // 分段序号 从1开始
+	Danmaku []*Danmaku // 该分段的弹幕 已去重并按出现时间排序
+	Err     error      // 获取失败时的错误，之后channel会被关闭
+}
+
+// DanmakuSegCount 根据视频时长计算实时弹幕的分段数，每6分钟一段
+//
+// duration: 视频时长 单位为秒 即 VideoPage.Duration
+func DanmakuSegCount(duration int64) int {
+	if duration <= 0 {
+		return 0
+	}
+	return int((duration + danmakuSegDuration - 1) / danmakuSegDuration)
+}
+
+// NewDanmakuResp 将弹幕分段的protobuf结构转换为 DanmakuResp，并将AI云屏蔽评分写入对应弹幕
+func NewDanmakuResp(reply *dm.DmSegMobileReply) *DanmakuResp {
+	scores := make(map[int64]uint32, len(reply.GetAiFlag().GetDmFlags()))
+	for _, f := range reply.GetAiFlag().GetDmFlags() {
+		scores[f.GetDmid()] = f.GetFlag()
+	}
+	r := &DanmakuResp{
+		State:   int(reply.GetState()),
+		Danmaku: make([]*Danmaku, 0, len(reply.GetElems())),
+	}
+	for _, elem := range reply.GetElems() {
+		r.Danmaku = append(r.Danmaku, &Danmaku{
+			ID:       uint64(elem.Id),
+			Progress: int64(elem.Progress),
+			Mode:     int(elem.Mode),
+			FontSize: int(elem.Fontsize),
+			Color:    int(elem.Color),
+			MidHash:  elem.MidHash,
+			Content:  elem.Content,
+			Ctime:    elem.Ctime,
+			Weight:   int(elem.Weight),
+			Action:   elem.Action,
+			Pool:     int(elem.Pool),
+			IDStr:    elem.IdStr,
+			Attr:     int(elem.Attr),
+			AIScore:  scores[elem.Id],
+		})
+	}
+	return r
+}
+
+// decodeDanmakuSeg 解析实时弹幕与历史弹幕共用的分段数据
+func decodeDanmakuSeg(data []byte) (*dm.DmSegMobileReply, error) {
+	var reply dm.DmSegMobileReply
+	if err := proto.Unmarshal(data, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// collectDanmakuSegs 获取全部分段，按dmid去重后按出现时间排序
+func collectDanmakuSegs(ctx context.Context, fetch func(seg int) (*DanmakuResp, error), segs int, parallel int) (*DanmakuResp, error) {
+	r := &DanmakuResp{}
+	seen := make(map[uint64]struct{})
+	err := fetchDanmakuSegs(ctx, fetch, segs, parallel, func(seg int, resp *DanmakuResp) bool {
+		r.Danmaku = appendDanmakuUnique(r.Danmaku, seen, resp.Danmaku)
+		if resp.State != 0 {
+			r.State = resp.State
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortDanmaku(r.Danmaku)
+	return r, nil
+}
+
+// streamDanmakuSegs 在新的goroutine中获取全部分段，按分段顺序写入channel
+func streamDanmakuSegs(ctx context.Context, fetch func(seg int) (*DanmakuResp, error), segs int, parallel int) <-chan *DanmakuSeg {
+	ch := make(chan *DanmakuSeg)
+	go func() {
+		defer close(ch)
+		seen := make(map[uint64]struct{})
+		send := func(s *DanmakuSeg) bool {
+			select {
+			case ch <- s:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		err := fetchDanmakuSegs(ctx, fetch, segs, parallel, func(seg int, resp *DanmakuResp) bool {
+			dms := appendDanmakuUnique(nil, seen, resp.Danmaku)
+			sortDanmaku(dms)
+			return send(&DanmakuSeg{Seg: seg, Danmaku: dms})
+		})
+		if err != nil {
+			send(&DanmakuSeg{Err: err})
+		}
+	}()
+	return ch
+}
+
+// fetchDanmakuSegs 并发获取分段，并按分段顺序调用emit，emit返回false时停止
+//
+// segs为0时表示分段数未知，将从第一段开始顺序获取，直到遇到空分段
+func fetchDanmakuSegs(ctx context.Context, fetch func(seg int) (*DanmakuResp, error), segs int, parallel int, emit func(seg int, r *DanmakuResp) bool) error {
+	if segs <= 0 {
+		for seg := 1; ; seg++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			r, err := fetch(seg)
+			if err != nil {
+				return errors.Wrapf(err, "segment %d", seg)
+			}
+			if len(r.Danmaku) == 0 || !emit(seg, r) {
+				return nil
+			}
+		}
+	}
+
+	if parallel <= 0 {
+		parallel = danmakuSegParallel
+	}
+	if parallel > segs {
+		parallel = segs
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		seg int
+		r   *DanmakuResp
+		err error
+	}
+	jobs := make(chan int)
+	results := make(chan *result)
+
+	go func() {
+		defer close(jobs)
+		for seg := 1; seg <= segs; seg++ {
+			select {
+			case jobs <- seg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(parallel)
+	for i := 0; i < parallel; i++ {
+		go func() {
+			defer wg.Done()
+			for seg := range jobs {
+				r, err := fetch(seg)
+				select {
+				case results <- &result{seg: seg, r: r, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 先完成的分段暂存，保证按顺序回调
+	pending := make(map[int]*DanmakuResp)
+	next := 1
+	for res := range results {
+		if res.err != nil {
+			return errors.Wrapf(res.err, "segment %d", res.seg)
+		}
+		pending[res.seg] = res.r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if !emit(next, r) {
+				return nil
+			}
+			next++
+		}
+	}
+	if next <= segs {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// appendDanmakuUnique 将未出现过的弹幕追加到dst
+func appendDanmakuUnique(dst []*Danmaku, seen map[uint64]struct{}, src []*Danmaku) []*Danmaku {
+	for _, d := range src {
+		if _, ok := seen[d.ID]; ok {
+			continue
+		}
+		seen[d.ID] = struct{}{}
+		dst = append(dst, d)
+	}
+	return dst
+}
+
+// sortDanmaku 按出现时间排序，时间相同时按dmid排序
+func sortDanmaku(dms []*Danmaku) {
+	sort.SliceStable(dms, func(i, j int) bool {
+		if dms[i].Progress != dms[j].Progress {
+			return dms[i].Progress < dms[j].Progress
+		}
+		return dms[i].ID < dms[j].ID
+	})
+}
@@ -0,0 +1,162 @@
+package biligo
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/iyear/biligo/proto/dm"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestSegFetcher 每个分段两条弹幕，相邻分段之间有一条重复
+func newTestSegFetcher(segs int, fail int) (func(seg int) (*DanmakuResp, error), *int32) {
+	var calls int32
+	return func(seg int) (*DanmakuResp, error) {
+		atomic.AddInt32(&calls, 1)
+		if seg == fail {
+			return nil, errors.New("fetch failed")
+		}
+		if seg > segs {
+			return &DanmakuResp{}, nil
+		}
+		// 打乱完成顺序
+		time.Sleep(time.Duration(segs-seg) * time.Millisecond)
+		base := int64(seg-1) * danmakuSegDuration * 1000
+		return &DanmakuResp{Danmaku: []*Danmaku{
+			{ID: uint64(seg*10 + 1), Progress: base + 2000},
+			{ID: uint64(seg * 10), Progress: base + 1000},
+			// 与下一个分段重复
+			{ID: uint64((seg+1)*10 + 1), Progress: base + danmakuSegDuration*1000 + 2000},
+		}}, nil
+	}, &calls
+}
+
+func TestNewDanmakuResp(t *testing.T) {
+	data, err := proto.Marshal(&dm.DmSegMobileReply{
+		Elems: []*dm.DanmakuElem{
+			{Id: 1, Progress: 1000, Content: "前排", IdStr: "1"},
+			{Id: 2, Progress: 2000, Content: "2333", IdStr: "2"},
+		},
+		State:  1,
+		AiFlag: &dm.DanmakuAIFlag{DmFlags: []*dm.DanmakuFlag{{Dmid: 2, Flag: 7}}},
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	reply, err := decodeDanmakuSeg(data)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	r := NewDanmakuResp(reply)
+	if r.State != 1 || len(r.Danmaku) != 2 {
+		t.Errorf("%+v", r)
+		t.FailNow()
+	}
+	if r.Danmaku[0].AIScore != 0 || r.Danmaku[1].AIScore != 7 || r.Danmaku[1].Content != "2333" {
+		t.Errorf("%+v %+v", r.Danmaku[0], r.Danmaku[1])
+		t.FailNow()
+	}
+	if _, err = decodeDanmakuSeg([]byte{0xff}); err == nil {
+		t.Error("invalid data should fail")
+		t.FailNow()
+	}
+}
+func TestDanmakuSegCount(t *testing.T) {
+	for d, n := range map[int64]int{0: 0, 1: 1, 360: 1, 361: 2, 3600: 10} {
+		if c := DanmakuSegCount(d); c != n {
+			t.Errorf("duration: %d,want: %d,got: %d", d, n, c)
+			t.FailNow()
+		}
+	}
+}
+func TestCollectDanmakuSegs(t *testing.T) {
+	fetch, _ := newTestSegFetcher(5, 0)
+	r, err := collectDanmakuSegs(context.Background(), fetch, 5, 3)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// 5个分段各两条 加上第5段带出的第6段的一条
+	if len(r.Danmaku) != 11 {
+		t.Errorf("num: %d", len(r.Danmaku))
+		t.FailNow()
+	}
+	for i := 1; i < len(r.Danmaku); i++ {
+		if r.Danmaku[i-1].Progress > r.Danmaku[i].Progress {
+			t.Errorf("not sorted at %d", i)
+			t.FailNow()
+		}
+	}
+}
+func TestCollectDanmakuSegs2(t *testing.T) {
+	// 分段数未知时顺序获取直到空分段
+	fetch, calls := newTestSegFetcher(3, 0)
+	r, err := collectDanmakuSegs(context.Background(), fetch, 0, 0)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(r.Danmaku) != 7 || atomic.LoadInt32(calls) != 4 {
+		t.Errorf("num: %d,calls: %d", len(r.Danmaku), *calls)
+		t.FailNow()
+	}
+}
+func TestCollectDanmakuSegs3(t *testing.T) {
+	fetch, _ := newTestSegFetcher(8, 4)
+	if _, err := collectDanmakuSegs(context.Background(), fetch, 8, 2); err == nil {
+		t.Error("want error when a segment fails")
+		t.FailNow()
+	}
+}
+func TestStreamDanmakuSegs(t *testing.T) {
+	fetch, _ := newTestSegFetcher(6, 0)
+	next := 1
+	for s := range streamDanmakuSegs(context.Background(), fetch, 6, 4) {
+		if s.Err != nil {
+			t.Error(s.Err)
+			t.FailNow()
+		}
+		if s.Seg != next {
+			t.Errorf("want seg: %d,got: %d", next, s.Seg)
+			t.FailNow()
+		}
+		// 重复的弹幕只在第一次出现的分段中返回
+		if n := len(s.Danmaku); next == 1 && n != 3 || next > 1 && n != 2 {
+			t.Errorf("seg: %d,num: %d", s.Seg, n)
+			t.FailNow()
+		}
+		next++
+	}
+}
+func TestStreamDanmakuSegs2(t *testing.T) {
+	// 提前取消时后台goroutine退出，channel关闭
+	fetch, _ := newTestSegFetcher(20, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := streamDanmakuSegs(ctx, fetch, 20, 4)
+	<-ch
+	cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range ch {
+		}
+	}()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Error("channel is not closed after cancel")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,134 @@
+package biligo
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/xml"
+	"fmt"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type danmakuXML struct {
+	XMLName    xml.Name          `xml:"i"`
+	ChatServer string            `xml:"chatserver"`
+	ChatID     int64             `xml:"chatid"`
+	Mission    int               `xml:"mission"`
+	MaxLimit   int               `xml:"maxlimit"`
+	State      int               `xml:"state"`
+	RealName   int               `xml:"real_name"`
+	Source     string            `xml:"source"`
+	D          []*danmakuXMLItem `xml:"d"`
+}
+type danmakuXMLItem struct {
+	P       string `xml:"p,attr"`
+	Content string `xml:",chardata"`
+}
+
+// DanmakuToXML 将弹幕转换为旧版XML格式，可用于各类播放器及弹幕转换工具
+//
+// cid: 写入chatid
+//
+// p属性依次为 出现时间(秒) 类型 字号 颜色 发送时间 弹幕池 发送者mid hash dmid 权重
+//
+// XML格式中没有 Attr Action AIScore 字段，转换时会丢失，需要保留 State 时使用 DanmakuRespToXML
+func DanmakuToXML(cid int64, danmaku []*Danmaku) ([]byte, error) {
+	return DanmakuRespToXML(cid, &DanmakuResp{Danmaku: danmaku})
+}
+
+// DanmakuRespToXML 与 DanmakuToXML 相同，同时将 State 写入state
+func DanmakuRespToXML(cid int64, r *DanmakuResp) ([]byte, error) {
+	doc := &danmakuXML{
+		ChatServer: "chat.bilibili.com",
+		ChatID:     cid,
+		MaxLimit:   len(r.Danmaku),
+		State:      r.State,
+		Source:     "k-v",
+		D:          make([]*danmakuXMLItem, 0, len(r.Danmaku)),
+	}
+	for _, d := range r.Danmaku {
+		doc.D = append(doc.D, &danmakuXMLItem{
+			P: fmt.Sprintf("%.5f,%d,%d,%d,%d,%d,%s,%d,%d",
+				float64(d.Progress)/1000, d.Mode, d.FontSize, d.Color, d.Ctime, d.Pool, d.MidHash, d.ID, d.Weight),
+			Content: d.Content,
+		})
+	}
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// ParseDanmakuXML 解析旧版XML格式弹幕
+//
+// 兼容没有权重字段的旧数据，此时 Weight 为0
+//
+// Attr Action AIScore 不在XML格式中，解析结果中均为零值
+func ParseDanmakuXML(data []byte) (*DanmakuResp, error) {
+	var doc danmakuXML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	r := &DanmakuResp{State: doc.State, Danmaku: make([]*Danmaku, 0, len(doc.D))}
+	for _, item := range doc.D {
+		d, err := parseDanmakuXMLItem(item)
+		if err != nil {
+			return nil, err
+		}
+		r.Danmaku = append(r.Danmaku, d)
+	}
+	return r, nil
+}
+
+func parseDanmakuXMLItem(item *danmakuXMLItem) (*Danmaku, error) {
+	p := strings.Split(item.P, ",")
+	if len(p) < 8 {
+		return nil, errors.Errorf("invalid danmaku attr: %s", item.P)
+	}
+	progress, err := strconv.ParseFloat(p[0], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid progress: %s", item.P)
+	}
+	var ints [5]int64
+	for i, v := range []string{p[1], p[2], p[3], p[4], p[5]} {
+		if ints[i], err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, errors.Wrapf(err, "invalid danmaku attr: %s", item.P)
+		}
+	}
+	id, err := strconv.ParseUint(p[7], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid dmid: %s", item.P)
+	}
+	d := &Danmaku{
+		ID:       id,
+		IDStr:    p[7],
+		Progress: int64(math.Round(progress * 1000)),
+		Mode:     int(ints[0]),
+		FontSize: int(ints[1]),
+		Color:    int(ints[2]),
+		Ctime:    ints[3],
+		Pool:     int(ints[4]),
+		MidHash:  p[6],
+		Content:  item.Content,
+	}
+	if len(p) > 8 {
+		if d.Weight, err = strconv.Atoi(p[8]); err != nil {
+			return nil, errors.Wrapf(err, "invalid weight: %s", item.P)
+		}
+	}
+	return d, nil
+}
+
+// inflateDanmakuXML list.so返回的数据经过deflate压缩，未压缩时原样返回
+func inflateDanmakuXML(raw []byte) ([]byte, error) {
+	if t := bytes.TrimSpace(raw); len(t) > 0 && t[0] == '<' {
+		return raw, nil
+	}
+	r := flate.NewReader(bytes.NewReader(raw))
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
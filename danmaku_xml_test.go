@@ -0,0 +1,98 @@
+package biligo
+
+import (
+	"bytes"
+	"compress/flate"
+	"strings"
+	"testing"
+)
+
+func TestDanmakuToXML(t *testing.T) {
+	dms := []*Danmaku{
+		{ID: 52582735853867523, IDStr: "52582735853867523", Progress: 407, Mode: 1, FontSize: 25, Color: 16777215, MidHash: "ad8ea5d1", Content: "<前方高能&>", Ctime: 1625454032, Weight: 10},
+		{ID: 52582735853867524, IDStr: "52582735853867524", Progress: 61234, Mode: 5, FontSize: 18, Color: 255, MidHash: "1a2b3c4d", Content: "字幕池", Ctime: 1625454033, Pool: 1, Weight: 3},
+	}
+	b, err := DanmakuToXML(1176840, dms)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Log(string(b))
+	if !strings.Contains(string(b), `<d p="0.40700,1,25,16777215,1625454032,0,ad8ea5d1,52582735853867523,10">&lt;前方高能&amp;&gt;</d>`) {
+		t.FailNow()
+	}
+
+	r, err := ParseDanmakuXML(b)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(r.Danmaku) != len(dms) {
+		t.Errorf("num: %d", len(r.Danmaku))
+		t.FailNow()
+	}
+	for i, d := range r.Danmaku {
+		if *d != *dms[i] {
+			t.Errorf("want: %+v,got: %+v", dms[i], d)
+			t.FailNow()
+		}
+	}
+}
+func TestDanmakuRespToXML(t *testing.T) {
+	src := &DanmakuResp{State: 1, Danmaku: []*Danmaku{
+		{ID: 1, IDStr: "1", Progress: 1000, Mode: 1, FontSize: 25, Color: 16777215, MidHash: "ad8ea5d1", Content: "已关闭", Ctime: 1625454032, Weight: 5},
+	}}
+	b, err := DanmakuRespToXML(1176840, src)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if !strings.Contains(string(b), "<state>1</state>") {
+		t.Error(string(b))
+		t.FailNow()
+	}
+	r, err := ParseDanmakuXML(b)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if r.State != src.State || len(r.Danmaku) != 1 || *r.Danmaku[0] != *src.Danmaku[0] {
+		t.Errorf("want: %+v,got: %+v", src, r)
+		t.FailNow()
+	}
+}
+func TestParseDanmakuXML(t *testing.T) {
+	// 没有权重字段的旧数据
+	r, err := ParseDanmakuXML([]byte(`<i><chatid>1</chatid><d p="1.5,4,25,0,1,0,abc,123">旧</d></i>`))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if d := r.Danmaku[0]; d.Progress != 1500 || d.Mode != 4 || d.ID != 123 || d.Weight != 0 || d.Content != "旧" {
+		t.Errorf("%+v", d)
+		t.FailNow()
+	}
+	if _, err = ParseDanmakuXML([]byte(`<i><d p="1.5,4,25">错误</d></i>`)); err == nil {
+		t.Error("want error when attr is incomplete")
+		t.FailNow()
+	}
+}
+func TestInflateDanmakuXML(t *testing.T) {
+	src := []byte(`<?xml version="1.0" encoding="UTF-8"?><i></i>`)
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	w.Write(src)
+	w.Close()
+
+	for _, raw := range [][]byte{src, buf.Bytes()} {
+		b, err := inflateDanmakuXML(raw)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if !bytes.Equal(b, src) {
+			t.Error(string(b))
+			t.FailNow()
+		}
+	}
+}
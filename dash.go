@@ -0,0 +1,204 @@
+package biligo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// MPDSetting 生成MPD时的配置，传入nil使用默认配置
+type MPDSetting struct {
+	// 改写流地址，可用于让所有分段都经过自己的代理
+	//
+	// 默认不改写
+	RewriteURL func(u string) string
+	// 是否同时写入备用流地址，写入后播放器会在主地址失效时自动切换
+	//
+	// 默认false
+	BackupURL bool
+}
+
+type mpd struct {
+	XMLName                   xml.Name   `xml:"MPD"`
+	XMLNS                     string     `xml:"xmlns,attr"`
+	Profiles                  string     `xml:"profiles,attr"`
+	Type                      string     `xml:"type,attr"`
+	MediaPresentationDuration string     `xml:"mediaPresentationDuration,attr"`
+	MinBufferTime             string     `xml:"minBufferTime,attr"`
+	Period                    *mpdPeriod `xml:"Period"`
+}
+type mpdPeriod struct {
+	Start          string              `xml:"start,attr"`
+	AdaptationSets []*mpdAdaptationSet `xml:"AdaptationSet"`
+}
+type mpdAdaptationSet struct {
+	ContentType      string               `xml:"contentType,attr"`
+	MimeType         string               `xml:"mimeType,attr"`
+	SegmentAlignment bool                 `xml:"segmentAlignment,attr"`
+	StartWithSAP     int                  `xml:"startWithSAP,attr,omitempty"`
+	Representations  []*mpdRepresentation `xml:"Representation"`
+}
+type mpdRepresentation struct {
+	ID          string          `xml:"id,attr"`
+	Bandwidth   int64           `xml:"bandwidth,attr"`
+	Codecs      string          `xml:"codecs,attr"`
+	Width       int             `xml:"width,attr,omitempty"`
+	Height      int             `xml:"height,attr,omitempty"`
+	FrameRate   string          `xml:"frameRate,attr,omitempty"`
+	Sar         string          `xml:"sar,attr,omitempty"`
+	BaseURL     []string        `xml:"BaseURL"`
+	SegmentBase *mpdSegmentBase `xml:"SegmentBase"`
+}
+type mpdSegmentBase struct {
+	IndexRange     string             `xml:"indexRange,attr"`
+	Initialization *mpdInitialization `xml:"Initialization"`
+}
+type mpdInitialization struct {
+	Range string `xml:"range,attr"`
+}
+
+// MPD 由dash音视频流信息生成 MPEG-DASH MPD 文档，可直接交给 dash.js Shaka VLC 等播放器使用
+//
+// 请求取流地址时 fnval 需要包含 dash(16)，否则没有dash信息会返回错误
+//
+// 视频流按 编码(Codecid) 分为不同的 AdaptationSet，播放器只会在同一编码内切换清晰度
+//
+// 注意流地址有效时间为120min，并且需要带上 Referer 请求，直接播放请使用 setting.RewriteURL 改为自己的代理地址
+func (r *VideoPlayURLResult) MPD(setting *MPDSetting) ([]byte, error) {
+	if r.Dash == nil {
+		return nil, errors.New("dash info not found")
+	}
+	if setting == nil {
+		setting = &MPDSetting{}
+	}
+
+	// TimeLength 更精确，缺失时退回到 Dash.Duration
+	duration := float64(r.TimeLength) / 1000
+	if duration <= 0 {
+		duration = float64(r.Dash.Duration)
+	}
+
+	period := &mpdPeriod{Start: "PT0S"}
+
+	// 按编码分组，保持原有顺序
+	var groups []*mpdAdaptationSet
+	index := make(map[string]*mpdAdaptationSet)
+	for _, v := range r.Dash.Video {
+		key := fmt.Sprintf("%s|%d", v.MimeType, v.Codecid)
+		set, ok := index[key]
+		if !ok {
+			set = &mpdAdaptationSet{
+				ContentType:      "video",
+				MimeType:         v.MimeType,
+				SegmentAlignment: true,
+				StartWithSAP:     v.StartWithSap,
+			}
+			index[key] = set
+			groups = append(groups, set)
+		}
+		set.Representations = append(set.Representations, newMPDRepresentation(v, true, setting))
+	}
+	period.AdaptationSets = append(period.AdaptationSets, groups...)
+
+	// 杜比与无损音频编码不同，各自单独作为一个 AdaptationSet
+	audios := [][]*VideoPlayURLDashMedia{r.Dash.Audio}
+	if r.Dash.Dolby != nil {
+		audios = append(audios, r.Dash.Dolby.Audio)
+	}
+	if r.Dash.Flac != nil && r.Dash.Flac.Audio != nil {
+		audios = append(audios, []*VideoPlayURLDashMedia{r.Dash.Flac.Audio})
+	}
+	for _, audio := range audios {
+		if len(audio) == 0 {
+			continue
+		}
+		set := &mpdAdaptationSet{
+			ContentType:      "audio",
+			MimeType:         audio[0].MimeType,
+			SegmentAlignment: true,
+			StartWithSAP:     audio[0].StartWithSap,
+		}
+		for _, a := range audio {
+			set.Representations = append(set.Representations, newMPDRepresentation(a, false, setting))
+		}
+		period.AdaptationSets = append(period.AdaptationSets, set)
+	}
+
+	doc := &mpd{
+		XMLNS:                     "urn:mpeg:dash:schema:mpd:2011",
+		Profiles:                  "urn:mpeg:dash:profile:isoff-on-demand:2011",
+		Type:                      "static",
+		MediaPresentationDuration: mpdDuration(duration),
+		MinBufferTime:             mpdDuration(r.Dash.MinBufferTime),
+		Period:                    period,
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func newMPDRepresentation(m *VideoPlayURLDashMedia, video bool, setting *MPDSetting) *mpdRepresentation {
+	rewrite := func(u string) string {
+		if setting.RewriteURL != nil {
+			return setting.RewriteURL(u)
+		}
+		return u
+	}
+
+	rep := &mpdRepresentation{
+		// 同一清晰度可能存在多种编码，id需要带上编码区分
+		ID:        fmt.Sprintf("%d-%d", m.ID, m.Codecid),
+		Bandwidth: m.Bandwidth,
+		Codecs:    m.Codecs,
+		BaseURL:   []string{rewrite(m.BaseURL)},
+	}
+	if video {
+		rep.Width = m.Width
+		rep.Height = m.Height
+		rep.FrameRate = mpdFrameRate(m.FrameRate)
+		rep.Sar = m.Sar
+	}
+	if setting.BackupURL {
+		for _, u := range m.BackupURL {
+			rep.BaseURL = append(rep.BaseURL, rewrite(u))
+		}
+	}
+	if m.SegmentBase != nil {
+		rep.SegmentBase = &mpdSegmentBase{
+			IndexRange:     m.SegmentBase.IndexRange,
+			Initialization: &mpdInitialization{Range: m.SegmentBase.Initialization},
+		}
+	}
+	return rep
+}
+
+// mpdDuration 秒转为 xs:duration
+func mpdDuration(sec float64) string {
+	return "PT" + strconv.FormatFloat(sec, 'f', -1, 64) + "S"
+}
+
+// mpdFrameRate MPD的frameRate只允许整数或分数，B站返回的 29.412 这类小数需要转为分数
+func mpdFrameRate(rate string) string {
+	i := strings.IndexByte(rate, '.')
+	if i < 0 {
+		return rate
+	}
+	frac := strings.TrimRight(rate[i+1:], "0")
+	if frac == "" {
+		return rate[:i]
+	}
+	num, err := strconv.ParseInt(rate[:i]+frac, 10, 64)
+	if err != nil {
+		return ""
+	}
+	den := int64(1)
+	for range frac {
+		den *= 10
+	}
+	return fmt.Sprintf("%d/%d", num, den)
+}
@@ -0,0 +1,124 @@
+package biligo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func newTestPlayURLResult() *VideoPlayURLResult {
+	return &VideoPlayURLResult{
+		Quality:    80,
+		TimeLength: 123456,
+		Dash: &VideoPlayURLDash{
+			Duration:      124,
+			MinBufferTime: 1.5,
+			Video: []*VideoPlayURLDashMedia{
+				{
+					ID: 80, BaseURL: "https://upos.example.com/80-avc.m4s?a=1&b=2", BackupURL: []string{"https://backup.example.com/80-avc.m4s"},
+					Bandwidth: 2000000, MimeType: "video/mp4", Codecs: "avc1.640032", Width: 1920, Height: 1080, FrameRate: "29.412", Sar: "1:1", StartWithSap: 1,
+					SegmentBase: &VideoPlayURLDashMediaSeg{Initialization: "0-1000", IndexRange: "1001-2000"}, Codecid: 7,
+				},
+				{
+					ID: 80, BaseURL: "https://upos.example.com/80-hevc.m4s",
+					Bandwidth: 1000000, MimeType: "video/mp4", Codecs: "hev1.1.6.L150.90", Width: 1920, Height: 1080, FrameRate: "30", Sar: "1:1", StartWithSap: 1,
+					SegmentBase: &VideoPlayURLDashMediaSeg{Initialization: "0-1100", IndexRange: "1101-2100"}, Codecid: 12,
+				},
+				{
+					ID: 64, BaseURL: "https://upos.example.com/64-avc.m4s",
+					Bandwidth: 1000000, MimeType: "video/mp4", Codecs: "avc1.640028", Width: 1280, Height: 720, FrameRate: "16000/544", Sar: "1:1", StartWithSap: 1,
+					SegmentBase: &VideoPlayURLDashMediaSeg{Initialization: "0-900", IndexRange: "901-1800"}, Codecid: 7,
+				},
+			},
+			Audio: []*VideoPlayURLDashMedia{
+				{
+					ID: 30280, BaseURL: "https://upos.example.com/30280.m4s",
+					Bandwidth: 320000, MimeType: "audio/mp4", Codecs: "mp4a.40.2", StartWithSap: 0,
+					SegmentBase: &VideoPlayURLDashMediaSeg{Initialization: "0-800", IndexRange: "801-1600"}, Codecid: 0,
+				},
+			},
+		},
+	}
+}
+
+func TestVideoPlayURLResult_MPD(t *testing.T) {
+	b, err := newTestPlayURLResult().MPD(&MPDSetting{
+		RewriteURL: func(u string) string {
+			return "http://127.0.0.1:8080/proxy?u=" + u
+		},
+		BackupURL: true,
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	t.Log(string(b))
+
+	var doc mpd
+	if err = xml.Unmarshal(b, &doc); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if doc.MediaPresentationDuration != "PT123.456S" || doc.MinBufferTime != "PT1.5S" {
+		t.Errorf("duration: %s,buffer: %s", doc.MediaPresentationDuration, doc.MinBufferTime)
+		t.FailNow()
+	}
+	// avc hevc audio
+	if n := len(doc.Period.AdaptationSets); n != 3 {
+		t.Errorf("adaptation sets: %d", n)
+		t.FailNow()
+	}
+	avc := doc.Period.AdaptationSets[0]
+	if len(avc.Representations) != 2 || avc.Representations[0].ID != "80-7" || avc.Representations[1].ID != "64-7" {
+		t.Errorf("avc: %+v", avc.Representations)
+		t.FailNow()
+	}
+	rep := avc.Representations[0]
+	if rep.FrameRate != "29412/1000" || len(rep.BaseURL) != 2 || !strings.HasPrefix(rep.BaseURL[1], "http://127.0.0.1:8080/proxy?u=") {
+		t.Errorf("rep: %+v", rep)
+		t.FailNow()
+	}
+	if rep.SegmentBase.IndexRange != "1001-2000" || rep.SegmentBase.Initialization.Range != "0-1000" {
+		t.Errorf("segment base: %+v", rep.SegmentBase)
+		t.FailNow()
+	}
+	if avc.Representations[1].FrameRate != "16000/544" {
+		t.Error(avc.Representations[1].FrameRate)
+		t.FailNow()
+	}
+	audio := doc.Period.AdaptationSets[2]
+	if audio.ContentType != "audio" || audio.Representations[0].Width != 0 {
+		t.Errorf("audio: %+v", audio)
+		t.FailNow()
+	}
+}
+func TestVideoPlayURLResult_MPD2(t *testing.T) {
+	if _, err := (&VideoPlayURLResult{}).MPD(nil); err == nil {
+		t.Error("want error when dash is nil")
+		t.FailNow()
+	}
+}
+func TestVideoPlayURLResult_MPD3(t *testing.T) {
+	r := newTestPlayURLResult()
+	r.Dash.Dolby = &VideoPlayURLDashDolby{Type: 1, Audio: []*VideoPlayURLDashMedia{{ID: 30250, MimeType: "audio/mp4", Codecs: "ec-3"}}}
+	r.Dash.Flac = &VideoPlayURLDashFlac{Display: true, Audio: &VideoPlayURLDashMedia{ID: 30251, MimeType: "audio/mp4", Codecs: "fLaC"}}
+	b, err := r.MPD(nil)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	var doc mpd
+	if err = xml.Unmarshal(b, &doc); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// avc hevc audio dolby flac
+	if n := len(doc.Period.AdaptationSets); n != 5 {
+		t.Errorf("adaptation sets: %d", n)
+		t.FailNow()
+	}
+	if c := doc.Period.AdaptationSets[4].Representations[0].Codecs; c != "fLaC" {
+		t.Error(c)
+		t.FailNow()
+	}
+}
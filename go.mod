@@ -3,7 +3,9 @@ module github.com/iyear/biligo
 go 1.16
 
 require (
+	github.com/andybalholm/brotli v1.0.4
 	github.com/golang/protobuf v1.5.2
+	github.com/gorilla/websocket v1.4.2
 	github.com/pkg/errors v0.9.1
 	github.com/tidwall/gjson v1.8.1
 	github.com/tidwall/pretty v1.2.0 // indirect
@@ -0,0 +1,140 @@
+// Package liveproto 直播间弹幕websocket的二进制协议
+//
+// 每个数据包由16字节头部与正文组成，头部依次为
+// 包长度(uint32) 头部长度(uint16) 协议版本(uint16) 操作码(uint32) 序列号(uint32)，均为大端序。
+// 协议版本为2或3时正文是zlib或brotli压缩后的多个数据包
+package liveproto
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"github.com/andybalholm/brotli"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+)
+
+// HeaderLen 头部长度
+const HeaderLen = 16
+
+// 协议版本
+const (
+	VerJSON   = 0 // 未压缩的JSON正文
+	VerInt    = 1 // 人气值、认证等
+	VerZlib   = 2 // zlib压缩
+	VerBrotli = 3 // brotli压缩
+)
+
+// 操作码
+const (
+	OpHeartbeat      = 2 // 客户端心跳
+	OpHeartbeatReply = 3 // 心跳回复 正文为4字节人气值
+	OpMessage        = 5 // 消息 正文为带有cmd字段的JSON
+	OpAuth           = 7 // 客户端认证
+	OpAuthReply      = 8 // 认证回复
+)
+
+// Packet 一个解压后的数据包
+type Packet struct {
+	Ver  uint16
+	Op   uint32
+	Seq  uint32
+	Body []byte
+}
+
+// Encode 编码数据包，ver为 VerZlib 或 VerBrotli 时压缩正文
+func Encode(p *Packet) ([]byte, error) {
+	body := p.Body
+	switch p.Ver {
+	case VerZlib, VerBrotli:
+		var err error
+		if body, err = compress(p.Ver, body); err != nil {
+			return nil, err
+		}
+	}
+	buf := make([]byte, HeaderLen+len(body))
+	binary.BigEndian.PutUint32(buf[0:], uint32(len(buf)))
+	binary.BigEndian.PutUint16(buf[4:], HeaderLen)
+	binary.BigEndian.PutUint16(buf[6:], p.Ver)
+	binary.BigEndian.PutUint32(buf[8:], p.Op)
+	binary.BigEndian.PutUint32(buf[12:], p.Seq)
+	copy(buf[HeaderLen:], body)
+	return buf, nil
+}
+
+// Decode 解码一条websocket消息，消息中可能有多个数据包，压缩的数据包会被解压展开
+func Decode(data []byte) ([]*Packet, error) {
+	var packets []*Packet
+	for len(data) > 0 {
+		if len(data) < HeaderLen {
+			return nil, errors.Errorf("short packet: %d bytes", len(data))
+		}
+		size := binary.BigEndian.Uint32(data[0:])
+		head := binary.BigEndian.Uint16(data[4:])
+		if size < uint32(head) || size > uint32(len(data)) || head < HeaderLen {
+			return nil, errors.Errorf("invalid packet length: %d header: %d", size, head)
+		}
+		p := &Packet{
+			Ver:  binary.BigEndian.Uint16(data[6:]),
+			Op:   binary.BigEndian.Uint32(data[8:]),
+			Seq:  binary.BigEndian.Uint32(data[12:]),
+			Body: data[head:size],
+		}
+		data = data[size:]
+
+		switch p.Ver {
+		case VerZlib, VerBrotli:
+			body, err := decompress(p.Ver, p.Body)
+			if err != nil {
+				return nil, err
+			}
+			inner, err := Decode(body)
+			if err != nil {
+				return nil, errors.Wrap(err, "decode compressed packet")
+			}
+			packets = append(packets, inner...)
+		default:
+			packets = append(packets, p)
+		}
+	}
+	return packets, nil
+}
+
+func compress(ver uint16, data []byte) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+	)
+	if ver == VerZlib {
+		w = zlib.NewWriter(&buf)
+	} else {
+		w = brotli.NewWriter(&buf)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(ver uint16, data []byte) ([]byte, error) {
+	var r io.Reader
+	if ver == VerZlib {
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.Wrap(err, "zlib")
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = brotli.NewReader(bytes.NewReader(data))
+	}
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "decompress")
+	}
+	return raw, nil
+}
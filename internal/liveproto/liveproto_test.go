@@ -0,0 +1,61 @@
+package liveproto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	data, err := Encode(&Packet{Ver: VerInt, Op: OpHeartbeat, Seq: 1, Body: []byte("[object Object]")})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	want := []byte{0, 0, 0, 31, 0, 16, 0, 1, 0, 0, 0, 2, 0, 0, 0, 1}
+	if !bytes.Equal(data[:HeaderLen], want) || string(data[HeaderLen:]) != "[object Object]" {
+		t.Errorf("%v", data)
+		t.FailNow()
+	}
+}
+func TestDecode(t *testing.T) {
+	for _, ver := range []uint16{VerJSON, VerZlib, VerBrotli} {
+		var batch []byte
+		for _, body := range []string{`{"cmd":"A"}`, `{"cmd":"B"}`} {
+			p, err := Encode(&Packet{Ver: VerJSON, Op: OpMessage, Body: []byte(body)})
+			if err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+			batch = append(batch, p...)
+		}
+		data := batch
+		if ver != VerJSON {
+			var err error
+			if data, err = Encode(&Packet{Ver: ver, Op: OpMessage, Body: batch}); err != nil {
+				t.Error(err)
+				t.FailNow()
+			}
+		}
+		packets, err := Decode(data)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if len(packets) != 2 || string(packets[0].Body) != `{"cmd":"A"}` || string(packets[1].Body) != `{"cmd":"B"}` || packets[1].Op != OpMessage {
+			t.Errorf("ver %d: %+v", ver, packets)
+			t.FailNow()
+		}
+	}
+}
+func TestDecode2(t *testing.T) {
+	for _, data := range [][]byte{
+		{0, 0, 0, 16},
+		{0, 0, 0, 99, 0, 16, 0, 0, 0, 0, 0, 5, 0, 0, 0, 0},
+		{0, 0, 0, 20, 0, 16, 0, 2, 0, 0, 0, 5, 0, 0, 0, 0, 1, 2, 3, 4},
+	} {
+		if _, err := Decode(data); err == nil {
+			t.Errorf("%v should fail", data)
+			t.FailNow()
+		}
+	}
+}
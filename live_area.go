@@ -0,0 +1,103 @@
+package biligo
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// 分区直播间的排序方式 即 LiveGetAreaRoomList 的sort参数
+const (
+	LiveAreaSortDefault  = ""          // 综合
+	LiveAreaSortOnline   = "online"    // 人气
+	LiveAreaSortLiveTime = "live_time" // 最新开播
+)
+
+// LiveAreaTree 直播分区树
+type LiveAreaTree struct {
+	Areas []*LiveArea // 父分区 顺序与接口返回一致
+
+	parents map[int]*LiveArea
+	subs    map[int]*LiveSubArea
+}
+
+// NewLiveAreaTree 由 LiveGetAreaInfo 的结果构建分区树
+func NewLiveAreaTree(info []*LiveAreaInfo) (*LiveAreaTree, error) {
+	t := &LiveAreaTree{
+		parents: make(map[int]*LiveArea),
+		subs:    make(map[int]*LiveSubArea),
+	}
+	for _, p := range info {
+		area := &LiveArea{ID: p.ID, Name: p.Name}
+		for _, l := range p.List {
+			sub, err := newLiveSubArea(p, l.ID, l.ParentID, l.OldAreaID, l.LockStatus)
+			if err != nil {
+				return nil, errors.Wrapf(err, "area %s", l.Name)
+			}
+			sub.Name = l.Name
+			sub.Pic = l.Pic
+			sub.HotStatus = l.HotStatus
+			sub.ComplexAreaName = l.ComplexAreaName
+			sub.AreaType = l.AreaType
+			area.Children = append(area.Children, sub)
+			t.subs[sub.ID] = sub
+		}
+		t.Areas = append(t.Areas, area)
+		t.parents[area.ID] = area
+	}
+	return t, nil
+}
+
+// newLiveSubArea 接口中子分区的数字字段均为字符串，空串视为0
+func newLiveSubArea(p *LiveAreaInfo, id, parentID, oldAreaID, lockStatus string) (*LiveSubArea, error) {
+	v := make([]int, 4)
+	for i, s := range []string{id, parentID, oldAreaID, lockStatus} {
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = n
+	}
+	sub := &LiveSubArea{ID: v[0], ParentID: v[1], OldAreaID: v[2], LockStatus: v[3], ParentName: p.Name}
+	if sub.ParentID == 0 {
+		sub.ParentID = p.ID
+	}
+	return sub, nil
+}
+
+// Parent 按ID查找父分区，不存在时返回nil
+func (t *LiveAreaTree) Parent(id int) *LiveArea {
+	return t.parents[id]
+}
+
+// Sub 按ID查找子分区，不存在时返回nil
+func (t *LiveAreaTree) Sub(id int) *LiveSubArea {
+	return t.subs[id]
+}
+
+// ParentByName 按名称查找父分区，不存在时返回nil
+func (t *LiveAreaTree) ParentByName(name string) *LiveArea {
+	for _, a := range t.Areas {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// SubByName 按名称查找子分区
+//
+// 不同父分区下可能有同名子分区(如 其他)，因此返回所有匹配项，按父分区顺序排列
+func (t *LiveAreaTree) SubByName(name string) []*LiveSubArea {
+	var r []*LiveSubArea
+	for _, a := range t.Areas {
+		for _, s := range a.Children {
+			if s.Name == name {
+				r = append(r, s)
+			}
+		}
+	}
+	return r
+}
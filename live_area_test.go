@@ -0,0 +1,83 @@
+package biligo
+
+import (
+	"encoding/json"
+	"github.com/iyear/biligo/livetest"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+const testLiveAreaInfo = `[
+	{"id":2,"name":"网游","list":[
+		{"id":"86","parent_id":"2","old_area_id":"4","name":"英雄联盟","lock_status":"0","hot_status":1,"parent_name":"网游"},
+		{"id":"107","parent_id":"2","old_area_id":"","name":"其他","lock_status":"1","parent_name":"网游"}
+	]},
+	{"id":6,"name":"单机游戏","list":[
+		{"id":"235","parent_id":"6","old_area_id":"1","name":"其他","lock_status":"0","parent_name":"单机游戏"}
+	]}
+]`
+
+func TestNewLiveAreaTree(t *testing.T) {
+	var info []*LiveAreaInfo
+	if err := json.Unmarshal([]byte(testLiveAreaInfo), &info); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	tree, err := NewLiveAreaTree(info)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(tree.Areas) != 2 || len(tree.Parent(2).Children) != 2 || tree.Parent(3) != nil {
+		t.Errorf("parents: %v", tree.Areas)
+	}
+	if s := tree.Sub(86); s == nil || s.ParentID != 2 || s.OldAreaID != 4 || s.HotStatus != 1 || s.ParentName != "网游" {
+		t.Errorf("sub 86: %+v", s)
+	}
+	if s := tree.Sub(107); s == nil || s.LockStatus != 1 || s.OldAreaID != 0 {
+		t.Errorf("sub 107: %+v", s)
+	}
+	if tree.Sub(1) != nil {
+		t.Error("sub 1 exists")
+	}
+	if p := tree.ParentByName("单机游戏"); p == nil || p.ID != 6 {
+		t.Errorf("parent by name: %+v", p)
+	}
+	if subs := tree.SubByName("其他"); len(subs) != 2 || subs[0].ID != 107 || subs[1].ID != 235 {
+		t.Errorf("sub by name: %v", subs)
+	}
+
+	info[0].List[0].ID = "x"
+	if _, err = NewLiveAreaTree(info); err == nil {
+		t.Error("expect error")
+	}
+}
+
+func TestLiveAreaRoomIter(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	s.Handle("xlive/web-interface/v1/second/getList", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		pn, _ := strconv.Atoi(q.Get("page"))
+		if q.Get("parent_area_id") != "2" || q.Get("area_id") != "86" || q.Get("sort_type") != LiveAreaSortOnline {
+			t.Errorf("query: %s", r.URL.RawQuery)
+		}
+		items := []map[string]interface{}{{"roomid": pn*10 + 1}, {"roomid": pn*10 + 2}}
+		livetest.WriteData(w, map[string]interface{}{"count": 6, "has_more": map[bool]int{true: 1}[pn < 3], "list": items})
+	})
+	c := NewCommClient(&CommSetting{Client: s.Client()})
+
+	it := c.LiveAreaRoomIter(2, 86, LiveAreaSortOnline)
+	var rooms []int64
+	for it.Next() {
+		rooms = append(rooms, it.Item().RoomID)
+	}
+	if err := it.Err(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(rooms) != 6 || rooms[0] != 11 || rooms[5] != 32 {
+		t.Errorf("rooms: %v", rooms)
+	}
+}
@@ -0,0 +1,399 @@
+package biligo
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/iyear/biligo/internal/liveproto"
+	"github.com/pkg/errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LiveCmdPopularity 心跳回复中的人气值消息，非B站原有的cmd
+const LiveCmdPopularity = "POPULARITY"
+
+// LiveMsg 直播间消息
+type LiveMsg struct {
+	// 消息类型 如 DANMU_MSG SEND_GIFT
+	//
+	// 已去除部分消息携带的 :4:0:2:2:2:0 等后缀，原始cmd见 Data
+	Cmd        string
+	Data       json.RawMessage // 消息原始JSON 人气值消息为空
+	Popularity int64           // 人气值 仅 LiveCmdPopularity
+	Time       time.Time       // 收到消息的时间
+}
+
+// LiveConnSetting 直播间连接配置，传入nil使用默认配置
+type LiveConnSetting struct {
+	// 用于获取直播间信息与服务器列表
+	//
+	// 默认为 NewCommClient(&CommSetting{})
+	Comm *CommClient
+	// 携带登录信息连接，收到的用户名等信息不会被打码
+	//
+	// 默认匿名连接
+	Bili *BiliClient
+	// 消息回调，在读取消息的goroutine中依次调用，阻塞会导致心跳超时
+	//
+	// 为nil时消息写入 LiveConn.Msg
+	Handler func(msg *LiveMsg)
+	// 连接断开时的回调，之后会自动重连
+	OnDisconnect func(err error)
+	// 心跳间隔
+	//
+	// 默认30s
+	Heartbeat time.Duration
+	// 连续连接失败的次数上限，超过后不再重连，负数为不限
+	//
+	// 默认为服务器数量的3倍
+	Retry int
+	// 尝试完所有服务器后的重连间隔
+	//
+	// 默认3s
+	RetryInterval time.Duration
+	// 自定义websocket dialer，可用于设置代理
+	//
+	// 默认为 websocket.DefaultDialer
+	Dialer *websocket.Dialer
+}
+
+// LiveConn 直播间弹幕websocket连接，断开后自动在服务器列表间重连
+type LiveConn struct {
+	RoomID  int64 // 真实直播间ID
+	ShortID int   // 直播间短号 没有时为0
+	UID     int64 // 主播mid
+
+	setting *LiveConnSetting
+	conf    *LiveWsConf
+	host    int // 下一次连接使用的服务器
+	msg     chan *LiveMsg
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+
+	mu sync.Mutex // 保护ws及其写入
+	ws *websocket.Conn
+}
+
+// NewLiveConn 连接直播间
+//
+// roomID: 可为短号也可以是真实房号
+//
+// 首次连接失败时直接返回错误，连接成功后断线会自动重连
+func NewLiveConn(roomID int64, setting *LiveConnSetting) (*LiveConn, error) {
+	s := LiveConnSetting{}
+	if setting != nil {
+		s = *setting
+	}
+	if s.Comm == nil {
+		s.Comm = NewCommClient(&CommSetting{})
+	}
+	if s.Heartbeat <= 0 {
+		s.Heartbeat = 30 * time.Second
+	}
+	if s.RetryInterval <= 0 {
+		s.RetryInterval = 3 * time.Second
+	}
+	if s.Dialer == nil {
+		s.Dialer = websocket.DefaultDialer
+	}
+
+	info, err := s.Comm.LiveGetRoomInfoByID(roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get room info")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &LiveConn{
+		RoomID:  info.RoomID,
+		ShortID: info.ShortID,
+		UID:     info.UID,
+		setting: &s,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	if s.Handler == nil {
+		c.msg = make(chan *LiveMsg, 64)
+	}
+	if err = c.refreshConf(); err != nil {
+		cancel()
+		return nil, err
+	}
+	if c.setting.Retry == 0 {
+		c.setting.Retry = len(c.conf.HostServerList) * 3
+	}
+
+	ws, err := c.connect()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go c.run(ws)
+	return c, nil
+}
+
+// Msg 未设置 LiveConnSetting.Handler 时，消息从该channel读取，连接停止后关闭
+//
+// 不及时读取会阻塞接收消息，导致心跳超时
+func (c *LiveConn) Msg() <-chan *LiveMsg {
+	return c.msg
+}
+
+// Done 连接停止时关闭
+func (c *LiveConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err 连接停止的原因，调用 Close 停止时为nil
+func (c *LiveConn) Err() error {
+	<-c.done
+	return c.err
+}
+
+// Close 断开连接并停止重连，等待读取消息的goroutine退出
+//
+// 会等待 Handler 返回，不要在 Handler 中调用
+func (c *LiveConn) Close() error {
+	c.cancel()
+	c.mu.Lock()
+	if c.ws != nil {
+		_ = c.ws.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		_ = c.ws.Close()
+	}
+	c.mu.Unlock()
+	<-c.done
+	return nil
+}
+
+func (c *LiveConn) run(ws *websocket.Conn) {
+	defer func() {
+		if c.msg != nil {
+			close(c.msg)
+		}
+		close(c.done)
+	}()
+	for {
+		err := c.serve(ws)
+		if c.ctx.Err() != nil {
+			return
+		}
+		if c.setting.OnDisconnect != nil {
+			c.setting.OnDisconnect(err)
+		}
+		if ws, err = c.connect(); err != nil {
+			if c.ctx.Err() == nil {
+				c.err = err
+			}
+			return
+		}
+	}
+}
+
+func (c *LiveConn) refreshConf() error {
+	var (
+		conf *LiveWsConf
+		err  error
+	)
+	if c.setting.Bili != nil {
+		conf, err = c.setting.Bili.LiveGetWsConf(c.RoomID)
+	} else {
+		conf, err = c.setting.Comm.LiveGetWsConf(c.RoomID)
+	}
+	if err != nil {
+		return errors.Wrap(err, "get ws conf")
+	}
+	if len(conf.HostServerList) == 0 {
+		return errors.New("empty host server list")
+	}
+	c.conf = conf
+	return nil
+}
+
+// connect 依次尝试服务器列表，一轮失败后刷新token并等待重连间隔
+func (c *LiveConn) connect() (*websocket.Conn, error) {
+	var last error
+	for failures := 0; c.setting.Retry < 0 || failures < c.setting.Retry; failures++ {
+		if failures > 0 && c.host == 0 {
+			select {
+			case <-time.After(c.setting.RetryInterval):
+			case <-c.ctx.Done():
+				return nil, c.ctx.Err()
+			}
+			if err := c.refreshConf(); err != nil {
+				last = err
+				continue
+			}
+		}
+		h := c.conf.HostServerList[c.host]
+		c.host = (c.host + 1) % len(c.conf.HostServerList)
+
+		ws, err := c.dial(fmt.Sprintf("wss://%s:%d/sub", h.Host, h.WssPort))
+		if err == nil {
+			c.host = 0
+			return ws, nil
+		}
+		if c.ctx.Err() != nil {
+			return nil, c.ctx.Err()
+		}
+		last = errors.Wrapf(err, "connect %s", h.Host)
+	}
+	return nil, last
+}
+
+// dial 建立连接并认证
+func (c *LiveConn) dial(link string) (*websocket.Conn, error) {
+	ws, _, err := c.setting.Dialer.DialContext(c.ctx, link, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := map[string]interface{}{
+		"uid":      0,
+		"roomid":   c.RoomID,
+		"protover": liveproto.VerBrotli,
+		"platform": "web",
+		"type":     2,
+		"key":      c.conf.Token,
+	}
+	if c.setting.Bili != nil && c.setting.Bili.Me != nil {
+		auth["uid"] = c.setting.Bili.Me.MID
+	}
+	body, err := json.Marshal(auth)
+	if err != nil {
+		ws.Close()
+		return nil, err
+	}
+	if err = c.write(ws, liveproto.OpAuth, body); err != nil {
+		ws.Close()
+		return nil, err
+	}
+
+	_ = ws.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		ws.Close()
+		return nil, errors.Wrap(err, "read auth reply")
+	}
+	packets, err := liveproto.Decode(data)
+	if err != nil {
+		ws.Close()
+		return nil, err
+	}
+	if len(packets) == 0 || packets[0].Op != liveproto.OpAuthReply {
+		ws.Close()
+		return nil, errors.New("unexpected auth reply")
+	}
+	var reply struct {
+		Code int `json:"code"`
+	}
+	if err = json.Unmarshal(packets[0].Body, &reply); err != nil || reply.Code != 0 {
+		ws.Close()
+		return nil, errors.Errorf("auth failed: %s", packets[0].Body)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 连接期间被Close
+	if c.ctx.Err() != nil {
+		ws.Close()
+		return nil, c.ctx.Err()
+	}
+	c.ws = ws
+	return ws, nil
+}
+
+// serve 发送心跳并读取消息，直到连接断开
+func (c *LiveConn) serve(ws *websocket.Conn) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(c.setting.Heartbeat)
+		defer ticker.Stop()
+		for {
+			c.mu.Lock()
+			err := c.write(ws, liveproto.OpHeartbeat, []byte("[object Object]"))
+			c.mu.Unlock()
+			if err != nil {
+				ws.Close()
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	defer ws.Close()
+	for {
+		// 服务器每次心跳都会回复，超过两个心跳周期没有数据视为断开
+		_ = ws.SetReadDeadline(time.Now().Add(2*c.setting.Heartbeat + 10*time.Second))
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			return err
+		}
+		packets, err := liveproto.Decode(data)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, p := range packets {
+			var m *LiveMsg
+			switch p.Op {
+			case liveproto.OpHeartbeatReply:
+				if len(p.Body) < 4 {
+					continue
+				}
+				m = &LiveMsg{Cmd: LiveCmdPopularity, Popularity: int64(binary.BigEndian.Uint32(p.Body)), Time: now}
+			case liveproto.OpMessage:
+				var head struct {
+					Cmd string `json:"cmd"`
+				}
+				if err = json.Unmarshal(p.Body, &head); err != nil {
+					return errors.Wrapf(err, "invalid message: %s", p.Body)
+				}
+				if i := strings.IndexByte(head.Cmd, ':'); i >= 0 {
+					head.Cmd = head.Cmd[:i]
+				}
+				m = &LiveMsg{Cmd: head.Cmd, Data: p.Body, Time: now}
+			default:
+				continue
+			}
+			if !c.deliver(m) {
+				return c.ctx.Err()
+			}
+		}
+	}
+}
+
+func (c *LiveConn) deliver(m *LiveMsg) bool {
+	if c.setting.Handler != nil {
+		c.setting.Handler(m)
+		return true
+	}
+	select {
+	case c.msg <- m:
+		return true
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
+// write 调用方需要保证同一时间只有一个写入
+func (c *LiveConn) write(ws *websocket.Conn, op uint32, body []byte) error {
+	data, err := liveproto.Encode(&liveproto.Packet{Ver: liveproto.VerInt, Op: op, Seq: 1, Body: body})
+	if err != nil {
+		return err
+	}
+	_ = ws.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return ws.WriteMessage(websocket.BinaryMessage, data)
+}
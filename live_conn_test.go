@@ -0,0 +1,172 @@
+package biligo
+
+import (
+	"github.com/iyear/biligo/livetest"
+	"testing"
+	"time"
+)
+
+func newTestLiveConn(s *livetest.Server, setting *LiveConnSetting) (*LiveConn, error) {
+	if setting == nil {
+		setting = &LiveConnSetting{}
+	}
+	setting.Comm = NewCommClient(&CommSetting{Client: s.Client()})
+	setting.Dialer = s.Dialer()
+	setting.RetryInterval = 10 * time.Millisecond
+	if s.ShortID != 0 {
+		return NewLiveConn(int64(s.ShortID), setting)
+	}
+	return NewLiveConn(s.RoomID, setting)
+}
+func recvLiveMsg(t *testing.T, c *LiveConn, cmd string) *LiveMsg {
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case m, ok := <-c.Msg():
+			if !ok {
+				t.Error("msg channel closed")
+				t.FailNow()
+			}
+			if m.Cmd == cmd {
+				return m
+			}
+		case <-timeout:
+			t.Errorf("wait %s timeout", cmd)
+			t.FailNow()
+		}
+	}
+}
+func TestNewLiveConn(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	s.ShortID = 1
+	s.SetPopularity(233)
+
+	c, err := newTestLiveConn(s, &LiveConnSetting{Heartbeat: 50 * time.Millisecond})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer c.Close()
+	if c.RoomID != 287083 || c.ShortID != 1 {
+		t.Errorf("%+v", c)
+		t.FailNow()
+	}
+	if auths := s.Auths(); len(auths) != 1 || auths[0].RoomID != 287083 || auths[0].Key != s.Token || auths[0].Protover != 3 {
+		t.Errorf("%+v", auths)
+		t.FailNow()
+	}
+	if m := recvLiveMsg(t, c, LiveCmdPopularity); m.Popularity != 233 {
+		t.Errorf("%+v", m)
+		t.FailNow()
+	}
+
+	for _, compress := range []uint16{livetest.CompressNone, livetest.CompressZlib, livetest.CompressBrotli} {
+		s.SetCompress(compress)
+		if err = s.Send(`{"cmd":"DANMU_MSG:4:0:2:2:2:0","info":[[0,1,25,16777215],"2333",[1,"a"]]}`, `{"cmd":"LIVE","roomid":287083}`); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		m := recvLiveMsg(t, c, LiveCmdDanmaku)
+		v, err := ParseLiveEvent(m)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if d := v.(*LiveDanmaku); d.Content != "2333" {
+			t.Errorf("%+v", d)
+			t.FailNow()
+		}
+		recvLiveMsg(t, c, LiveCmdLive)
+	}
+}
+func TestNewLiveConn2(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	s.SetDeadHosts(2)
+
+	disconnected := make(chan error, 1)
+	danmaku := make(chan *LiveDanmaku, 1)
+	h := &LiveHandler{OnDanmaku: func(d *LiveDanmaku) { danmaku <- d }}
+	c, err := newTestLiveConn(s, &LiveConnSetting{
+		Handler:      h.Handle,
+		OnDisconnect: func(err error) { disconnected <- err },
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if c.Msg() != nil {
+		t.Error("msg channel should be nil with handler")
+		t.FailNow()
+	}
+
+	// 服务器断线后自动重连
+	s.Disconnect()
+	select {
+	case <-disconnected:
+	case <-time.After(3 * time.Second):
+		t.Error("wait disconnect timeout")
+		t.FailNow()
+	}
+	if err = s.WaitClients(1, 3*time.Second); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err = s.Send(`{"cmd":"DANMU_MSG","info":[[0,1,25,16777215],"reconnected",[1,"a"]]}`); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	select {
+	case d := <-danmaku:
+		if d.Content != "reconnected" {
+			t.Errorf("%+v", d)
+			t.FailNow()
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("wait danmaku timeout")
+		t.FailNow()
+	}
+
+	if err = c.Close(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err = c.Err(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+}
+func TestNewLiveConn3(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	s.RejectAuth(true)
+	if _, err := newTestLiveConn(s, &LiveConnSetting{Retry: 2}); err == nil {
+		t.Error("auth should fail")
+		t.FailNow()
+	}
+
+	// 重连失败次数达到上限后停止
+	s.RejectAuth(false)
+	c, err := newTestLiveConn(s, &LiveConnSetting{Retry: 2})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	s.RejectAuth(true)
+	s.Disconnect()
+	select {
+	case <-c.Done():
+	case <-time.After(3 * time.Second):
+		t.Error("wait done timeout")
+		t.FailNow()
+	}
+	if c.Err() == nil {
+		t.Error("err should not be nil")
+		t.FailNow()
+	}
+	if _, ok := <-c.Msg(); ok {
+		t.Error("msg channel should be closed")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,281 @@
+package biligo
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+	"strconv"
+	"strings"
+)
+
+// 常用的直播间消息类型
+const (
+	LiveCmdDanmaku         = "DANMU_MSG"
+	LiveCmdGift            = "SEND_GIFT"
+	LiveCmdComboGift       = "COMBO_SEND"
+	LiveCmdSuperChat       = "SUPER_CHAT_MESSAGE"
+	LiveCmdGuardBuy        = "GUARD_BUY"
+	LiveCmdInteract        = "INTERACT_WORD"
+	LiveCmdOnlineRankCount = "ONLINE_RANK_COUNT"
+	LiveCmdOnlineRank      = "ONLINE_RANK_V2"
+	LiveCmdRoomChange      = "ROOM_CHANGE"
+	LiveCmdLive            = "LIVE"
+	LiveCmdPreparing       = "PREPARING"
+	LiveCmdWarning         = "WARNING"
+	LiveCmdCutOff          = "CUT_OFF"
+)
+
+// ParseLiveEvent 将直播间消息解析为对应的结构体
+//
+// 返回值类型与cmd对应：
+// DANMU_MSG *LiveDanmaku，SEND_GIFT *LiveGift，COMBO_SEND *LiveComboGift，
+// SUPER_CHAT_MESSAGE *LiveSuperChat，GUARD_BUY *LiveGuardBuy，INTERACT_WORD *LiveInteract，
+// ONLINE_RANK_COUNT *LiveOnlineRankCount，ONLINE_RANK_V2 *LiveOnlineRank，ROOM_CHANGE *LiveRoomChange，
+// LIVE PREPARING *LiveStatusChange，WARNING CUT_OFF *LiveWarning
+//
+// 人气值消息与其他未知类型的消息原样返回 *LiveMsg，可以自行解析 LiveMsg.Data
+func ParseLiveEvent(msg *LiveMsg) (interface{}, error) {
+	var (
+		v    interface{}
+		err  error
+		data = []byte(gjson.GetBytes(msg.Data, "data").Raw)
+	)
+	switch msg.Cmd {
+	case LiveCmdDanmaku:
+		v, err = parseLiveDanmaku(msg.Data)
+	case LiveCmdGift:
+		r := &LiveGift{}
+		if err = json.Unmarshal(data, r); err == nil {
+			r.Medal = liveMedalOrNil(r.Medal)
+		}
+		v = r
+	case LiveCmdComboGift:
+		r := &LiveComboGift{}
+		if err = json.Unmarshal(data, r); err == nil {
+			r.Medal = liveMedalOrNil(r.Medal)
+		}
+		v = r
+	case LiveCmdSuperChat:
+		r := &LiveSuperChat{}
+		if err = json.Unmarshal(data, r); err == nil {
+			r.Medal = liveMedalOrNil(r.Medal)
+		}
+		v = r
+	case LiveCmdGuardBuy:
+		r := &LiveGuardBuy{}
+		err = json.Unmarshal(data, r)
+		v = r
+	case LiveCmdInteract:
+		r := &LiveInteract{}
+		if err = json.Unmarshal(data, r); err == nil {
+			r.Medal = liveMedalOrNil(r.Medal)
+		}
+		v = r
+	case LiveCmdOnlineRankCount:
+		r := &LiveOnlineRankCount{}
+		err = json.Unmarshal(data, r)
+		v = r
+	case LiveCmdOnlineRank:
+		r := &LiveOnlineRank{}
+		err = json.Unmarshal(data, r)
+		v = r
+	case LiveCmdRoomChange:
+		r := &LiveRoomChange{}
+		err = json.Unmarshal(data, r)
+		v = r
+	case LiveCmdLive, LiveCmdPreparing:
+		// roomid 有时是字符串
+		v = &LiveStatusChange{
+			RoomID:   gjson.GetBytes(msg.Data, "roomid").Int(),
+			LiveTime: gjson.GetBytes(msg.Data, "live_time").Int(),
+		}
+	case LiveCmdWarning, LiveCmdCutOff:
+		v = &LiveWarning{
+			RoomID: gjson.GetBytes(msg.Data, "roomid").Int(),
+			Msg:    gjson.GetBytes(msg.Data, "msg").String(),
+		}
+	default:
+		return msg, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s", msg.Cmd)
+	}
+	return v, nil
+}
+
+// parseLiveDanmaku DANMU_MSG的info是按位置排列的数组
+func parseLiveDanmaku(raw []byte) (*LiveDanmaku, error) {
+	info := gjson.GetBytes(raw, "info")
+	if !info.IsArray() {
+		return nil, errors.New("missing info")
+	}
+	attr := info.Get("0")
+	user := info.Get("2")
+	d := &LiveDanmaku{
+		UID:        user.Get("0").Int(),
+		Uname:      user.Get("1").String(),
+		Content:    info.Get("1").String(),
+		Mode:       int(attr.Get("1").Int()),
+		FontSize:   int(attr.Get("2").Int()),
+		Color:      attr.Get("3").Int(),
+		Timestamp:  attr.Get("4").Int(),
+		IsAdmin:    user.Get("2").Int() == 1,
+		UserLevel:  int(info.Get("4.0").Int()),
+		GuardLevel: int(info.Get("7").Int()),
+	}
+	// 0:等级 1:勋章名 2:主播昵称 3:直播间ID 4:颜色 10:大航海等级 11:是否点亮 12:主播mid
+	if m := info.Get("3"); m.IsArray() && len(m.Array()) > 0 {
+		d.Medal = &LiveMedal{
+			Level:      int(m.Get("0").Int()),
+			Name:       m.Get("1").String(),
+			AnchorName: m.Get("2").String(),
+			RoomID:     m.Get("3").Int(),
+			Color:      m.Get("4").Int(),
+			GuardLevel: int(m.Get("10").Int()),
+			IsLighted:  int(m.Get("11").Int()),
+			TargetID:   m.Get("12").Int(),
+		}
+	}
+	// 非表情弹幕时为字符串 "{}"
+	if e := attr.Get("13"); e.IsObject() && e.Get("url").Exists() {
+		d.Emoticon = &LiveEmoticon{
+			Unique: e.Get("emoticon_unique").String(),
+			URL:    e.Get("url").String(),
+			Width:  int(e.Get("width").Int()),
+			Height: int(e.Get("height").Int()),
+		}
+	}
+	return d, nil
+}
+
+// UnmarshalJSON medal_color 在不同消息中可能是数字或 #RRGGBB 字符串
+func (m *LiveMedal) UnmarshalJSON(data []byte) error {
+	type medal LiveMedal
+	// 外层的同名字段会遮蔽 medal_color，避免字符串颜色解析失败
+	raw := struct {
+		*medal
+		Color json.RawMessage `json:"medal_color"`
+	}{medal: (*medal)(m)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c := gjson.ParseBytes(raw.Color)
+	if c.Type == gjson.String {
+		color, err := strconv.ParseInt(strings.TrimPrefix(c.String(), "#"), 16, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid medal color: %s", c.String())
+		}
+		m.Color = color
+		return nil
+	}
+	m.Color = c.Int()
+	return nil
+}
+
+// liveMedalOrNil 未佩戴勋章时B站仍会返回空的勋章信息
+func liveMedalOrNil(m *LiveMedal) *LiveMedal {
+	if m == nil || m.Level == 0 && m.Name == "" {
+		return nil
+	}
+	return m
+}
+
+// LiveHandler 按消息类型分发直播间消息，未设置的回调会被忽略
+//
+// 可以直接作为 LiveConnSetting.Handler 使用：
+//
+//	h := &biligo.LiveHandler{OnDanmaku: func(d *biligo.LiveDanmaku) { ... }}
+//	conn, err := biligo.NewLiveConn(roomID, &biligo.LiveConnSetting{Handler: h.Handle})
+type LiveHandler struct {
+	OnDanmaku         func(d *LiveDanmaku)
+	OnGift            func(g *LiveGift)
+	OnComboGift       func(g *LiveComboGift)
+	OnSuperChat       func(sc *LiveSuperChat)
+	OnGuardBuy        func(g *LiveGuardBuy)
+	OnInteract        func(i *LiveInteract)
+	OnOnlineRankCount func(c *LiveOnlineRankCount)
+	OnOnlineRank      func(r *LiveOnlineRank)
+	OnRoomChange      func(r *LiveRoomChange)
+	OnLive            func(s *LiveStatusChange)
+	OnPreparing       func(s *LiveStatusChange)
+	OnWarning         func(w *LiveWarning)
+	OnCutOff          func(w *LiveWarning)
+	OnPopularity      func(popularity int64)
+	// 没有对应结构体的消息
+	OnRaw func(msg *LiveMsg)
+	// 消息解析失败
+	OnError func(msg *LiveMsg, err error)
+}
+
+// Handle 解析并分发一条消息
+func (h *LiveHandler) Handle(msg *LiveMsg) {
+	if msg.Cmd == LiveCmdPopularity {
+		if h.OnPopularity != nil {
+			h.OnPopularity(msg.Popularity)
+		}
+		return
+	}
+	v, err := ParseLiveEvent(msg)
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(msg, err)
+		}
+		return
+	}
+	switch e := v.(type) {
+	case *LiveDanmaku:
+		if h.OnDanmaku != nil {
+			h.OnDanmaku(e)
+		}
+	case *LiveGift:
+		if h.OnGift != nil {
+			h.OnGift(e)
+		}
+	case *LiveComboGift:
+		if h.OnComboGift != nil {
+			h.OnComboGift(e)
+		}
+	case *LiveSuperChat:
+		if h.OnSuperChat != nil {
+			h.OnSuperChat(e)
+		}
+	case *LiveGuardBuy:
+		if h.OnGuardBuy != nil {
+			h.OnGuardBuy(e)
+		}
+	case *LiveInteract:
+		if h.OnInteract != nil {
+			h.OnInteract(e)
+		}
+	case *LiveOnlineRankCount:
+		if h.OnOnlineRankCount != nil {
+			h.OnOnlineRankCount(e)
+		}
+	case *LiveOnlineRank:
+		if h.OnOnlineRank != nil {
+			h.OnOnlineRank(e)
+		}
+	case *LiveRoomChange:
+		if h.OnRoomChange != nil {
+			h.OnRoomChange(e)
+		}
+	case *LiveStatusChange:
+		if msg.Cmd == LiveCmdLive && h.OnLive != nil {
+			h.OnLive(e)
+		}
+		if msg.Cmd == LiveCmdPreparing && h.OnPreparing != nil {
+			h.OnPreparing(e)
+		}
+	case *LiveWarning:
+		if msg.Cmd == LiveCmdWarning && h.OnWarning != nil {
+			h.OnWarning(e)
+		}
+		if msg.Cmd == LiveCmdCutOff && h.OnCutOff != nil {
+			h.OnCutOff(e)
+		}
+	case *LiveMsg:
+		if h.OnRaw != nil {
+			h.OnRaw(e)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package biligo
+
+import (
+	"testing"
+)
+
+func TestParseLiveEvent(t *testing.T) {
+	msg := &LiveMsg{Cmd: LiveCmdDanmaku, Data: []byte(`{"cmd":"DANMU_MSG:4:0:2:2:2:0","info":[[0,1,25,16777215,1633000000000,1633000000,0,"abc",0,0,0,"",0,{"emoticon_unique":"room_1_1","url":"https://i0.hdslb.com/a.png","width":183,"height":60}],"2333",[1001,"测试用户",1,0,0,10000,1,""],[21,"勋章","主播",287083,1725515,"",0,6809855,1725515,5414290,3,1,2000],[25,0,5805790,">50000"],["",""],0,3,null,{"ts":1633000000,"ct":"ABC"},0,0,null,null,0,105]}`)}
+	v, err := ParseLiveEvent(msg)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	d, ok := v.(*LiveDanmaku)
+	if !ok || d.UID != 1001 || d.Uname != "测试用户" || d.Content != "2333" || d.Color != 16777215 || !d.IsAdmin ||
+		d.UserLevel != 25 || d.GuardLevel != 3 || d.Timestamp != 1633000000000 {
+		t.Errorf("%+v", v)
+		t.FailNow()
+	}
+	if d.Medal == nil || d.Medal.Level != 21 || d.Medal.Name != "勋章" || d.Medal.RoomID != 287083 || d.Medal.TargetID != 2000 || d.Medal.GuardLevel != 3 {
+		t.Errorf("%+v", d.Medal)
+		t.FailNow()
+	}
+	if d.Emoticon == nil || d.Emoticon.Width != 183 {
+		t.Errorf("%+v", d.Emoticon)
+		t.FailNow()
+	}
+}
+func TestParseLiveEvent2(t *testing.T) {
+	tests := []struct {
+		msg   *LiveMsg
+		check func(v interface{}) bool
+	}{
+		{&LiveMsg{Cmd: LiveCmdGift, Data: []byte(`{"cmd":"SEND_GIFT","data":{"uid":1,"uname":"a","giftId":31036,"giftName":"小花花","num":5,"price":100,"total_coin":500,"coin_type":"gold","medal_info":{"medal_level":0,"medal_name":"","medal_color":0}}}`)},
+			func(v interface{}) bool {
+				g, ok := v.(*LiveGift)
+				return ok && g.GiftID == 31036 && g.Num == 5 && g.Medal == nil
+			}},
+		{&LiveMsg{Cmd: LiveCmdSuperChat, Data: []byte(`{"cmd":"SUPER_CHAT_MESSAGE","data":{"id":1,"uid":2,"price":30,"message":"hi","user_info":{"uname":"b"},"medal_info":{"medal_level":5,"medal_name":"x","medal_color":"#1a544b","target_id":3}}}`)},
+			func(v interface{}) bool {
+				sc, ok := v.(*LiveSuperChat)
+				return ok && sc.Price == 30 && sc.UserInfo.Uname == "b" && sc.Medal.Color == 0x1a544b && sc.Medal.TargetID == 3
+			}},
+		{&LiveMsg{Cmd: LiveCmdInteract, Data: []byte(`{"cmd":"INTERACT_WORD","data":{"uid":4,"uname":"c","msg_type":2,"roomid":5,"fans_medal":{"medal_level":3,"medal_name":"y","medal_color":6126494}}}`)},
+			func(v interface{}) bool {
+				i, ok := v.(*LiveInteract)
+				return ok && i.MsgType == 2 && i.Medal.Color == 6126494
+			}},
+		{&LiveMsg{Cmd: LiveCmdPreparing, Data: []byte(`{"cmd":"PREPARING","roomid":"287083"}`)},
+			func(v interface{}) bool {
+				s, ok := v.(*LiveStatusChange)
+				return ok && s.RoomID == 287083
+			}},
+		{&LiveMsg{Cmd: LiveCmdCutOff, Data: []byte(`{"cmd":"CUT_OFF","msg":"违规","roomid":1}`)},
+			func(v interface{}) bool {
+				w, ok := v.(*LiveWarning)
+				return ok && w.Msg == "违规"
+			}},
+		{&LiveMsg{Cmd: "STOP_LIVE_ROOM_LIST", Data: []byte(`{"cmd":"STOP_LIVE_ROOM_LIST","data":{}}`)},
+			func(v interface{}) bool {
+				m, ok := v.(*LiveMsg)
+				return ok && m.Cmd == "STOP_LIVE_ROOM_LIST"
+			}},
+	}
+	for _, tt := range tests {
+		v, err := ParseLiveEvent(tt.msg)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if !tt.check(v) {
+			t.Errorf("%s: %+v", tt.msg.Cmd, v)
+			t.FailNow()
+		}
+	}
+}
+func TestLiveHandler_Handle(t *testing.T) {
+	var got []string
+	h := &LiveHandler{
+		OnDanmaku:    func(d *LiveDanmaku) { got = append(got, "danmaku:"+d.Content) },
+		OnLive:       func(s *LiveStatusChange) { got = append(got, "live") },
+		OnPopularity: func(p int64) { got = append(got, "popularity") },
+		OnRaw:        func(msg *LiveMsg) { got = append(got, "raw:"+msg.Cmd) },
+		OnError:      func(msg *LiveMsg, err error) { got = append(got, "error:"+msg.Cmd) },
+	}
+	for _, msg := range []*LiveMsg{
+		{Cmd: LiveCmdPopularity, Popularity: 1},
+		{Cmd: LiveCmdDanmaku, Data: []byte(`{"cmd":"DANMU_MSG","info":[[0,1,25,16777215],"hi",[1,"a"]]}`)},
+		{Cmd: LiveCmdDanmaku, Data: []byte(`{"cmd":"DANMU_MSG"}`)},
+		{Cmd: LiveCmdLive, Data: []byte(`{"cmd":"LIVE","roomid":1}`)},
+		{Cmd: LiveCmdPreparing, Data: []byte(`{"cmd":"PREPARING","roomid":1}`)},
+		{Cmd: "UNKNOWN", Data: []byte(`{"cmd":"UNKNOWN"}`)},
+	} {
+		h.Handle(msg)
+	}
+	want := []string{"popularity", "danmaku:hi", "error:DANMU_MSG", "live", "raw:UNKNOWN"}
+	if len(got) != len(want) {
+		t.Errorf("want %v,got %v", want, got)
+		t.FailNow()
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("want %v,got %v", want, got)
+			t.FailNow()
+		}
+	}
+}
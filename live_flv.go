@@ -0,0 +1,439 @@
+package biligo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"time"
+)
+
+// FLV tag类型
+const (
+	flvTagAudio  = 8
+	flvTagVideo  = 9
+	flvTagScript = 18
+)
+
+// flvHeader 带音视频标志的文件头，末尾4字节为 PreviousTagSize0
+var flvHeader = []byte{'F', 'L', 'V', 1, 5, 0, 0, 0, 9, 0, 0, 0, 0}
+
+type flvTag struct {
+	Type      uint8
+	Timestamp int64 // 单位ms
+	Data      []byte
+}
+
+// isKeyframe 视频关键帧，分割文件只在关键帧处进行
+func (t *flvTag) isKeyframe() bool {
+	return t.Type == flvTagVideo && len(t.Data) > 0 && t.Data[0]>>4 == 1
+}
+
+// isSequenceHeader AVC/HEVC 的 decoder configuration 或 AAC 的 AudioSpecificConfig
+func (t *flvTag) isSequenceHeader() bool {
+	if len(t.Data) < 2 {
+		return false
+	}
+	switch t.Type {
+	case flvTagVideo:
+		codec := t.Data[0] & 0x0f
+		return (codec == 7 || codec == 12) && t.Data[1] == 0
+	case flvTagAudio:
+		return t.Data[0]>>4 == 10 && t.Data[1] == 0
+	}
+	return false
+}
+
+func (t *flvTag) encode() []byte {
+	size := len(t.Data)
+	buf := make([]byte, 11+size+4)
+	buf[0] = t.Type
+	buf[1], buf[2], buf[3] = byte(size>>16), byte(size>>8), byte(size)
+	ts := uint32(t.Timestamp)
+	buf[4], buf[5], buf[6], buf[7] = byte(ts>>16), byte(ts>>8), byte(ts), byte(ts>>24)
+	copy(buf[11:], t.Data)
+	binary.BigEndian.PutUint32(buf[11+size:], uint32(11+size))
+	return buf
+}
+
+type flvReader struct {
+	r      *bufio.Reader
+	header bool
+}
+
+func newFLVReader(r io.Reader) *flvReader {
+	return &flvReader{r: bufio.NewReader(r)}
+}
+
+// next 读取下一个tag，流正常结束时返回 io.EOF
+func (f *flvReader) next() (*flvTag, error) {
+	if !f.header {
+		head := make([]byte, 9)
+		if _, err := io.ReadFull(f.r, head); err != nil {
+			return nil, err
+		}
+		if string(head[:3]) != "FLV" {
+			return nil, errors.New("invalid flv header")
+		}
+		size := int64(binary.BigEndian.Uint32(head[5:]))
+		if size < 9 {
+			return nil, errors.Errorf("invalid flv header size: %d", size)
+		}
+		// 跳过扩展头部与 PreviousTagSize0
+		if _, err := io.CopyN(ioutil.Discard, f.r, size-9+4); err != nil {
+			return nil, err
+		}
+		f.header = true
+	}
+	head := make([]byte, 11)
+	if _, err := io.ReadFull(f.r, head); err != nil {
+		return nil, err
+	}
+	size := int(head[1])<<16 | int(head[2])<<8 | int(head[3])
+	data := make([]byte, size+4)
+	if _, err := io.ReadFull(f.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return &flvTag{
+		Type:      head[0] & 0x1f,
+		Timestamp: int64(uint32(head[7])<<24 | uint32(head[4])<<16 | uint32(head[5])<<8 | uint32(head[6])),
+		Data:      data[:size],
+	}, nil
+}
+
+// flvFile 录制中的FLV文件，关闭时回写 onMetaData 中的时长与大小
+type flvFile struct {
+	f       *os.File
+	meta    *amfObject
+	metaPos int64 // onMetaData tag正文在文件中的偏移
+	size    int64
+	base    int64 // 文件中第一个tag的时间戳，文件内时间戳从0开始
+	last    int64
+	started bool
+}
+
+// createFLVFile 创建文件并写入元数据与序列头
+func createFLVFile(path string, meta *amfObject, headers []*flvTag) (*flvFile, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &amfObject{ECMA: true}
+	if meta != nil {
+		m.Props = append(m.Props, meta.Props...)
+	}
+	// 先占位，关闭时以相同长度回写
+	m.set("duration", float64(0))
+	m.set("filesize", float64(0))
+	file := &flvFile{f: f, meta: m, metaPos: int64(len(flvHeader)) + 11}
+
+	if err = file.writeRaw(flvHeader); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err = file.writeRaw((&flvTag{Type: flvTagScript, Data: file.metaData()}).encode()); err != nil {
+		f.Close()
+		return nil, err
+	}
+	for _, h := range headers {
+		if err = file.writeRaw((&flvTag{Type: h.Type, Data: h.Data}).encode()); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+func (f *flvFile) metaData() []byte {
+	return amfEncode("onMetaData", f.meta)
+}
+
+func (f *flvFile) writeRaw(b []byte) error {
+	n, err := f.f.Write(b)
+	f.size += int64(n)
+	return err
+}
+
+// write 写入tag，时间戳需要已经单调
+func (f *flvFile) write(t *flvTag) error {
+	if !f.started {
+		f.base = t.Timestamp
+		f.started = true
+	}
+	f.last = t.Timestamp
+	return f.writeRaw((&flvTag{Type: t.Type, Timestamp: t.Timestamp - f.base, Data: t.Data}).encode())
+}
+
+func (f *flvFile) duration() time.Duration {
+	return f.durationAt(f.last)
+}
+
+// durationAt 写入时间戳为ts的tag后的时长
+func (f *flvFile) durationAt(ts int64) time.Duration {
+	if !f.started {
+		return 0
+	}
+	return time.Duration(ts-f.base) * time.Millisecond
+}
+
+func (f *flvFile) close() error {
+	f.meta.set("duration", f.duration().Seconds())
+	f.meta.set("filesize", float64(f.size))
+	_, err := f.f.WriteAt(f.metaData(), f.metaPos)
+	if e := f.f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// parseFLVMeta 解析 onMetaData，不是元数据时返回nil
+func parseFLVMeta(data []byte) *amfObject {
+	values, err := amfDecode(data)
+	if err != nil || len(values) < 2 || values[0] != "onMetaData" {
+		return nil
+	}
+	m, _ := values[1].(*amfObject)
+	return m
+}
+
+type amfProp struct {
+	Key   string
+	Value interface{}
+}
+
+// amfObject AMF0的object与ECMA array，保留属性顺序
+type amfObject struct {
+	Props []*amfProp
+	ECMA  bool
+}
+
+func (o *amfObject) get(key string) (interface{}, bool) {
+	for _, p := range o.Props {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set 替换已有属性，不存在时追加
+func (o *amfObject) set(key string, v interface{}) {
+	for i, p := range o.Props {
+		if p.Key == key {
+			o.Props[i] = &amfProp{Key: key, Value: v}
+			return
+		}
+	}
+	o.Props = append(o.Props, &amfProp{Key: key, Value: v})
+}
+
+// amfDecode 依次解码AMF0值
+//
+// number date 为float64，boolean 为bool，string long string 为string，
+// object ECMA array 为 *amfObject，strict array 为 []interface{}，null undefined 为nil
+func amfDecode(data []byte) ([]interface{}, error) {
+	d := &amfDecoder{data: data}
+	var values []interface{}
+	for d.pos < len(d.data) {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+type amfDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *amfDecoder) read(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errors.New("amf: unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *amfDecoder) u16() (int, error) {
+	b, err := d.read(2)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(b)), nil
+}
+
+func (d *amfDecoder) u32() (int, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+func (d *amfDecoder) str(long bool) (string, error) {
+	var (
+		n   int
+		err error
+	)
+	if long {
+		n, err = d.u32()
+	} else {
+		n, err = d.u16()
+	}
+	if err != nil {
+		return "", err
+	}
+	b, err := d.read(n)
+	return string(b), err
+}
+
+func (d *amfDecoder) value() (interface{}, error) {
+	m, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	switch m[0] {
+	case 0, 11:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		// date 后有2字节时区，已废弃
+		if m[0] == 11 {
+			if _, err = d.read(2); err != nil {
+				return nil, err
+			}
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 1:
+		b, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case 2:
+		return d.str(false)
+	case 12:
+		return d.str(true)
+	case 5, 6:
+		return nil, nil
+	case 3:
+		return d.object(false)
+	case 8:
+		// 数量字段不可信，以结束标记为准
+		if _, err = d.u32(); err != nil {
+			return nil, err
+		}
+		return d.object(true)
+	case 10:
+		n, err := d.u32()
+		if err != nil {
+			return nil, err
+		}
+		arr := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			v, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	}
+	return nil, errors.Errorf("amf: unsupported marker %d", m[0])
+}
+
+func (d *amfDecoder) object(ecma bool) (*amfObject, error) {
+	o := &amfObject{ECMA: ecma}
+	for {
+		key, err := d.str(false)
+		if err != nil {
+			return nil, err
+		}
+		// 空key后跟 object end 标记
+		if key == "" && d.pos < len(d.data) && d.data[d.pos] == 9 {
+			d.pos++
+			return o, nil
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		o.Props = append(o.Props, &amfProp{Key: key, Value: v})
+	}
+}
+
+// amfEncode 编码AMF0值，类型与 amfDecode 对应
+func amfEncode(values ...interface{}) []byte {
+	var buf []byte
+	for _, v := range values {
+		buf = amfAppend(buf, v)
+	}
+	return buf
+}
+
+func amfAppend(buf []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case float64:
+		buf = append(buf, 0)
+		buf = appendUint64(buf, math.Float64bits(v))
+	case bool:
+		b := byte(0)
+		if v {
+			b = 1
+		}
+		buf = append(buf, 1, b)
+	case string:
+		if len(v) > math.MaxUint16 {
+			buf = append(buf, 12)
+			buf = appendUint32(buf, uint32(len(v)))
+		} else {
+			buf = append(buf, 2, byte(len(v)>>8), byte(len(v)))
+		}
+		buf = append(buf, v...)
+	case *amfObject:
+		if v.ECMA {
+			buf = append(buf, 8)
+			buf = appendUint32(buf, uint32(len(v.Props)))
+		} else {
+			buf = append(buf, 3)
+		}
+		for _, p := range v.Props {
+			buf = append(buf, byte(len(p.Key)>>8), byte(len(p.Key)))
+			buf = append(buf, p.Key...)
+			buf = amfAppend(buf, p.Value)
+		}
+		buf = append(buf, 0, 0, 9)
+	case []interface{}:
+		buf = append(buf, 10)
+		buf = appendUint32(buf, uint32(len(v)))
+		for _, e := range v {
+			buf = amfAppend(buf, e)
+		}
+	default:
+		buf = append(buf, 5)
+	}
+	return buf
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
@@ -0,0 +1,43 @@
+package biligo
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAMF(t *testing.T) {
+	meta := &amfObject{ECMA: true, Props: []*amfProp{
+		{Key: "width", Value: float64(1280)},
+		{Key: "stereo", Value: true},
+		{Key: "encoder", Value: "obs"},
+		{Key: "nested", Value: &amfObject{Props: []*amfProp{{Key: "a", Value: nil}}}},
+		{Key: "list", Value: []interface{}{float64(1), "2"}},
+	}}
+	data := amfEncode("onMetaData", meta)
+	m := parseFLVMeta(data)
+	if m == nil || !m.ECMA || len(m.Props) != 5 {
+		t.Errorf("%+v", m)
+		t.FailNow()
+	}
+	if v, _ := m.get("encoder"); v != "obs" {
+		t.Errorf("%v", v)
+	}
+	if v, _ := m.get("nested"); v.(*amfObject).ECMA || len(v.(*amfObject).Props) != 1 {
+		t.Errorf("%+v", v)
+	}
+	if !bytes.Equal(amfEncode("onMetaData", m), data) {
+		t.Error("re-encode mismatch")
+	}
+
+	// 替换同类型的值长度不变，用于回写元数据
+	m.set("width", float64(1920))
+	if len(amfEncode("onMetaData", m)) != len(data) {
+		t.Error("size changed")
+	}
+	if parseFLVMeta(amfEncode("onCuePoint", m)) != nil {
+		t.Error("not meta")
+	}
+	if _, err := amfDecode(data[:len(data)-2]); err == nil {
+		t.Error("expect error")
+	}
+}
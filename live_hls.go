@@ -0,0 +1,131 @@
+package biligo
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/pkg/errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type hlsPlaylist struct {
+	Sequence       int64         // 第一个分片的序号
+	TargetDuration time.Duration // 分片的最大时长
+	Map            string        // fMP4的初始化分片 ts格式为空
+	Segments       []*hlsSegment
+	End            bool     // 直播已结束
+	Variants       []string // master playlist 中的子列表
+}
+
+type hlsSegment struct {
+	Seq      int64
+	URI      string
+	Duration time.Duration
+}
+
+// parseHLSPlaylist 解析m3u8，相对路径基于base补全
+func parseHLSPlaylist(base *url.URL, data []byte) (*hlsPlaylist, error) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if !sc.Scan() || strings.TrimSpace(strings.TrimPrefix(sc.Text(), "\ufeff")) != "#EXTM3U" {
+		return nil, errors.New("invalid m3u8: missing #EXTM3U")
+	}
+	var (
+		p       = &hlsPlaylist{}
+		extinf  = time.Duration(-1)
+		variant bool
+		err     error
+	)
+	resolve := func(uri string) (string, error) {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid uri: %s", uri)
+		}
+		return base.ResolveReference(u).String(), nil
+	}
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"):
+			if p.Sequence, err = strconv.ParseInt(line[len("#EXT-X-MEDIA-SEQUENCE:"):], 10, 64); err != nil {
+				return nil, errors.Wrapf(err, "invalid media sequence: %s", line)
+			}
+		case strings.HasPrefix(line, "#EXT-X-TARGETDURATION:"):
+			d, err := strconv.ParseFloat(line[len("#EXT-X-TARGETDURATION:"):], 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid target duration: %s", line)
+			}
+			p.TargetDuration = time.Duration(d * float64(time.Second))
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			uri := hlsAttr(line[len("#EXT-X-MAP:"):], "URI")
+			if uri == "" {
+				return nil, errors.Errorf("missing map uri: %s", line)
+			}
+			if p.Map, err = resolve(uri); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "#EXTINF:"):
+			v := line[len("#EXTINF:"):]
+			if i := strings.IndexByte(v, ','); i >= 0 {
+				v = v[:i]
+			}
+			d, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid extinf: %s", line)
+			}
+			extinf = time.Duration(d * float64(time.Second))
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			variant = true
+		case line == "#EXT-X-ENDLIST":
+			p.End = true
+		case strings.HasPrefix(line, "#"):
+		default:
+			uri, err := resolve(line)
+			if err != nil {
+				return nil, err
+			}
+			if variant {
+				p.Variants = append(p.Variants, uri)
+				variant = false
+				continue
+			}
+			if extinf < 0 {
+				return nil, errors.Errorf("segment without extinf: %s", line)
+			}
+			p.Segments = append(p.Segments, &hlsSegment{
+				Seq:      p.Sequence + int64(len(p.Segments)),
+				URI:      uri,
+				Duration: extinf,
+			})
+			extinf = -1
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// hlsAttr 读取属性列表中的值，如 URI="h1.m4s",BYTERANGE="100@0"
+func hlsAttr(list, name string) string {
+	quoted := false
+	start := 0
+	for i := 0; i <= len(list); i++ {
+		// 引号内可能含有逗号
+		if i < len(list) && list[i] == '"' {
+			quoted = !quoted
+		}
+		if i < len(list) && (list[i] != ',' || quoted) {
+			continue
+		}
+		kv := list[start:i]
+		start = i + 1
+		if j := strings.IndexByte(kv, '='); j >= 0 && strings.TrimSpace(kv[:j]) == name {
+			return strings.Trim(kv[j+1:], "\"")
+		}
+	}
+	return ""
+}
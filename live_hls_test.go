@@ -0,0 +1,53 @@
+package biligo
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseHLSPlaylist(t *testing.T) {
+	base, _ := url.Parse("https://cn.example.com/live-bvc/1/index.m3u8?expires=1")
+	p, err := parseHLSPlaylist(base, []byte(`#EXTM3U
+#EXT-X-VERSION:7
+#EXT-X-MEDIA-SEQUENCE:100
+#EXT-X-TARGETDURATION:2
+#EXT-X-MAP:URI="h100.m4s?k=1,2"
+#EXTINF:1.5,
+100.m4s
+#EXTINF:2.000,title
+https://other.example.com/101.m4s
+`))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if p.Sequence != 100 || p.TargetDuration != 2*time.Second || p.End || len(p.Segments) != 2 {
+		t.Errorf("%+v", p)
+		t.FailNow()
+	}
+	if p.Map != "https://cn.example.com/live-bvc/1/h100.m4s?k=1,2" {
+		t.Error(p.Map)
+	}
+	if s := p.Segments[0]; s.Seq != 100 || s.URI != "https://cn.example.com/live-bvc/1/100.m4s" || s.Duration != 1500*time.Millisecond {
+		t.Errorf("%+v", s)
+	}
+	if s := p.Segments[1]; s.Seq != 101 || s.URI != "https://other.example.com/101.m4s" {
+		t.Errorf("%+v", s)
+	}
+
+	p, err = parseHLSPlaylist(base, []byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1\nsub/index.m3u8\n#EXT-X-ENDLIST\n"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(p.Variants) != 1 || p.Variants[0] != "https://cn.example.com/live-bvc/1/sub/index.m3u8" || !p.End {
+		t.Errorf("%+v", p)
+	}
+
+	for _, data := range []string{"", "1.m4s", "#EXTM3U\n1.m4s", "#EXTM3U\n#EXTINF:x,\n1.m4s"} {
+		if _, err = parseHLSPlaylist(base, []byte(data)); err == nil {
+			t.Errorf("expect error: %q", data)
+		}
+	}
+}
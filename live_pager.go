@@ -0,0 +1,221 @@
+package biligo
+
+// livePager 分页迭代的公共部分，fetch获取第pn页并返回该页数量与是否还有下一页
+type livePager struct {
+	pn    int
+	i     int
+	n     int
+	last  bool
+	err   error
+	fetch func(pn int) (n int, more bool, err error)
+}
+
+// Next 移动到下一项，需要时请求下一页，没有更多数据或出错时返回false
+func (p *livePager) Next() bool {
+	if p.err != nil {
+		return false
+	}
+	p.i++
+	for p.i >= p.n {
+		if p.last {
+			return false
+		}
+		p.pn++
+		n, more, err := p.fetch(p.pn)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.i, p.n, p.last = 0, n, !more || n == 0
+	}
+	return true
+}
+
+// Err 迭代中遇到的错误，正常结束时为nil
+func (p *livePager) Err() error {
+	return p.err
+}
+
+// Page 当前项所在的页码
+func (p *livePager) Page() int {
+	return p.pn
+}
+
+// liveHasMore 本页不为空且已获取的数量小于总数，没有总数时直到空页为止
+//
+// 服务端会限制每页数量，不能以本页少于ps判断是否结束
+func liveHasMore(got, total, n int) bool {
+	return n > 0 && (total <= 0 || got < total)
+}
+
+// LiveOnlineGoldRankIter 高能榜迭代器
+//
+//	it := c.LiveOnlineGoldRankIter(roomID, mid, 50)
+//	for it.Next() {
+//		u := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type LiveOnlineGoldRankIter struct {
+	*livePager
+	items []*LiveOnlineGoldRankItem
+}
+
+// Item 当前项
+func (it *LiveOnlineGoldRankIter) Item() *LiveOnlineGoldRankItem {
+	return it.items[it.i]
+}
+
+// LiveOnlineGoldRankIter 按页遍历高能榜，参数同 LiveGetOnlineGoldRank
+func (c *CommClient) LiveOnlineGoldRankIter(roomID int64, mid int64, ps int) *LiveOnlineGoldRankIter {
+	it := &LiveOnlineGoldRankIter{}
+	got := 0
+	it.livePager = &livePager{fetch: func(pn int) (int, bool, error) {
+		r, err := c.LiveGetOnlineGoldRank(roomID, mid, pn, ps)
+		if err != nil {
+			return 0, false, err
+		}
+		it.items = r.OnlineRankItem
+		got += len(it.items)
+		return len(it.items), liveHasMore(got, r.OnlineNum, len(it.items)), nil
+	}}
+	return it
+}
+
+// LiveFansMemberIter 粉丝团成员迭代器，用法同 LiveOnlineGoldRankIter
+type LiveFansMemberIter struct {
+	*livePager
+	items []*LiveFansMember
+}
+
+// Item 当前项
+func (it *LiveFansMemberIter) Item() *LiveFansMember {
+	return it.items[it.i]
+}
+
+// LiveFansMemberIter 按页遍历粉丝团成员，参数同 LiveGetFansMembers
+func (c *CommClient) LiveFansMemberIter(mid int64, ps int) *LiveFansMemberIter {
+	it := &LiveFansMemberIter{}
+	got := 0
+	it.livePager = &livePager{fetch: func(pn int) (int, bool, error) {
+		r, err := c.LiveGetFansMembers(mid, pn, ps)
+		if err != nil {
+			return 0, false, err
+		}
+		it.items = r.Item
+		got += len(it.items)
+		return len(it.items), liveHasMore(got, r.Num, len(it.items)), nil
+	}}
+	return it
+}
+
+// LiveRecordIter 往期直播记录迭代器，用法同 LiveOnlineGoldRankIter
+type LiveRecordIter struct {
+	*livePager
+	items []*LiveRecord
+}
+
+// Item 当前项
+func (it *LiveRecordIter) Item() *LiveRecord {
+	return it.items[it.i]
+}
+
+// LiveRecordIter 按页遍历往期直播记录，参数同 LiveGetRecordList
+func (c *CommClient) LiveRecordIter(roomID int64, ps int) *LiveRecordIter {
+	it := &LiveRecordIter{}
+	got := 0
+	it.livePager = &livePager{fetch: func(pn int) (int, bool, error) {
+		r, err := c.LiveGetRecordList(roomID, pn, ps)
+		if err != nil {
+			return 0, false, err
+		}
+		it.items = r.List
+		got += len(it.items)
+		return len(it.items), liveHasMore(got, r.Count, len(it.items)), nil
+	}}
+	return it
+}
+
+// LiveReplayIter 直播回放迭代器，用法同 LiveOnlineGoldRankIter
+type LiveReplayIter struct {
+	*livePager
+	items []*LiveReplay
+}
+
+// Item 当前项
+func (it *LiveReplayIter) Item() *LiveReplay {
+	return it.items[it.i]
+}
+
+// LiveReplayIter 按页遍历自己的直播回放，参数同 LiveGetReplayList
+func (b *BiliClient) LiveReplayIter(ps int) *LiveReplayIter {
+	it := &LiveReplayIter{}
+	got := 0
+	it.livePager = &livePager{fetch: func(pn int) (int, bool, error) {
+		r, err := b.LiveGetReplayList(pn, ps)
+		if err != nil {
+			return 0, false, err
+		}
+		it.items = r.ReplayInfo
+		got += len(it.items)
+		total := 0
+		if r.Pagination != nil {
+			total = r.Pagination.Total
+		}
+		return len(it.items), liveHasMore(got, total, len(it.items)), nil
+	}}
+	return it
+}
+
+// LiveAreaRoomIter 分区直播间迭代器，用法同 LiveOnlineGoldRankIter
+type LiveAreaRoomIter struct {
+	*livePager
+	items []*LiveAreaRoom
+}
+
+// Item 当前项
+func (it *LiveAreaRoomIter) Item() *LiveAreaRoom {
+	return it.items[it.i]
+}
+
+// LiveAreaRoomIter 按页遍历分区下的直播间，参数同 LiveGetAreaRoomList
+//
+// 直播间在翻页期间可能因排序变化而重复出现，需要时请按 RoomID 去重
+func (c *CommClient) LiveAreaRoomIter(parentAreaID int, areaID int, sort string) *LiveAreaRoomIter {
+	it := &LiveAreaRoomIter{}
+	it.livePager = &livePager{fetch: func(pn int) (int, bool, error) {
+		r, err := c.LiveGetAreaRoomList(parentAreaID, areaID, sort, pn)
+		if err != nil {
+			return 0, false, err
+		}
+		it.items = r.List
+		return len(it.items), r.HasMore == 1, nil
+	}}
+	return it
+}
+
+// LiveFollowingIter 关注的直播间迭代器，用法同 LiveOnlineGoldRankIter
+type LiveFollowingIter struct {
+	*livePager
+	items []*LiveFollowingRoom
+}
+
+// Item 当前项
+func (it *LiveFollowingIter) Item() *LiveFollowingRoom {
+	return it.items[it.i]
+}
+
+// LiveFollowingIter 按页遍历关注的主播中正在直播的直播间，参数同 LiveGetFollowingLive
+func (b *BiliClient) LiveFollowingIter(ps int) *LiveFollowingIter {
+	it := &LiveFollowingIter{}
+	got := 0
+	it.livePager = &livePager{fetch: func(pn int) (int, bool, error) {
+		r, err := b.LiveGetFollowingLive(pn, ps)
+		if err != nil {
+			return 0, false, err
+		}
+		it.items = r.List
+		got += len(it.items)
+		return len(it.items), liveHasMore(got, r.Count, len(it.items)), nil
+	}}
+	return it
+}
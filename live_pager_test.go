@@ -0,0 +1,91 @@
+package biligo
+
+import (
+	"github.com/iyear/biligo/livetest"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestLiveFansMemberIter(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	var (
+		pages []int
+		limit = 30 // 服务端限制的每页最大数量
+		num   = 5  // 为0时模拟没有返回总数
+	)
+	s.Handle("xlive/general-interface/v1/rank/getFansMembersRank", func(w http.ResponseWriter, r *http.Request) {
+		pn, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		ps, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		if ps > limit {
+			ps = limit
+		}
+		pages = append(pages, pn)
+		var items []map[string]interface{}
+		for i := (pn-1)*ps + 1; i <= pn*ps && i <= 5; i++ {
+			items = append(items, map[string]interface{}{"uid": i, "rank": i})
+		}
+		livetest.WriteData(w, map[string]interface{}{"num": num, "item": items})
+	})
+	c := NewCommClient(&CommSetting{Client: s.Client()})
+
+	it := c.LiveFansMemberIter(s.UID, 2)
+	var uids []int64
+	for it.Next() {
+		if it.Page() != (len(uids))/2+1 {
+			t.Errorf("page: %d", it.Page())
+		}
+		uids = append(uids, it.Item().UID)
+	}
+	if err := it.Err(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(uids) != 5 || uids[4] != 5 || len(pages) != 3 {
+		t.Errorf("%v %v", uids, pages)
+	}
+	if it.Next() {
+		t.Error("next after end")
+	}
+
+	// 总数正好是整页时不会多请求一页
+	pages = nil
+	it = c.LiveFansMemberIter(s.UID, 5)
+	for it.Next() {
+	}
+	if it.Err() != nil || len(pages) != 1 {
+		t.Errorf("%v %v", it.Err(), pages)
+	}
+
+	// 服务端每页最多返回2项，少于ps时仍继续请求
+	pages, limit = nil, 2
+	it = c.LiveFansMemberIter(s.UID, 5)
+	uids = nil
+	for it.Next() {
+		uids = append(uids, it.Item().UID)
+	}
+	if it.Err() != nil || len(uids) != 5 || len(pages) != 3 {
+		t.Errorf("%v %v %v", it.Err(), uids, pages)
+	}
+
+	// 没有总数时直到空页为止
+	pages, num = nil, 0
+	it = c.LiveFansMemberIter(s.UID, 5)
+	uids = nil
+	for it.Next() {
+		uids = append(uids, it.Item().UID)
+	}
+	if it.Err() != nil || len(uids) != 5 || len(pages) != 4 {
+		t.Errorf("%v %v %v", it.Err(), uids, pages)
+	}
+
+	s.Handle("xlive/general-interface/v1/rank/getFansMembersRank", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":-400,"message":"请求错误"}`))
+	})
+	it = c.LiveFansMemberIter(s.UID, 2)
+	if it.Next() || it.Err() == nil {
+		t.Error("expect error")
+	}
+}
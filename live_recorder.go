@@ -0,0 +1,796 @@
+package biligo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 录制格式
+const (
+	LiveRecordFLV = "flv" // http_stream FLV直播流 文件扩展名 .flv
+	LiveRecordHLS = "hls" // http_hls fMP4直播流 文件扩展名 .mp4，没有fMP4时使用ts，扩展名 .ts
+)
+
+// errLiveStreamExpired 直播流地址到达有效期，需要重新获取
+var errLiveStreamExpired = errors.New("live stream url expired")
+
+// URLs 所有CDN的完整直播流地址
+func (c *LiveStreamCodec) URLs() []string {
+	urls := make([]string, 0, len(c.URLInfo))
+	for _, u := range c.URLInfo {
+		urls = append(urls, u.Host+c.BaseURL+u.Extra)
+	}
+	return urls
+}
+
+// LiveRecordSetting 直播录制配置，传入nil使用默认配置
+type LiveRecordSetting struct {
+	// 用于获取直播间信息与直播流，并使用其http client下载直播流
+	//
+	// 默认为 NewCommClient(&CommSetting{})
+	Comm *CommClient
+	// 期望的清晰度 原画:10000 蓝光:400 超清:250 高清:150 流畅:80 不可用时使用较低的清晰度
+	//
+	// 默认10000
+	Qn int
+	// 录制格式 LiveRecordFLV 或 LiveRecordHLS
+	//
+	// 默认 LiveRecordFLV
+	Format string
+	// 保存目录，不存在时创建
+	//
+	// 默认为当前目录
+	Dir string
+	// 文件名 不含扩展名，start为本场录制的开始时间，part从1开始
+	//
+	// 默认为 房间号_开始时间_part
+	FileName func(roomID int64, start time.Time, part int) string
+	// 单个文件的大小上限 单位为字节，0为不限
+	SplitSize int64
+	// 单个文件的时长上限，0为不限
+	//
+	// FLV在关键帧处分割，HLS在分片处分割，实际文件会略大于上限
+	SplitDuration time.Duration
+	// 同时录制直播弹幕，每个文件关闭时写入同名的 .xml 文件
+	//
+	// 弹幕的出现时间以收到弹幕时与文件开始录制的时间差计算
+	Danmaku bool
+	// 录制弹幕时的连接配置，其 Handler 仍会收到所有消息
+	LiveConn *LiveConnSetting
+	// 文件录制完成时的回调 path为视频文件路径
+	OnFile func(path string)
+	// 可恢复的错误，如获取直播流失败、连接中断，之后会自动重试
+	OnError func(err error)
+	// 获取直播流失败后的重试间隔
+	//
+	// 默认5s
+	RetryInterval time.Duration
+	// 未开播时检查开播状态的间隔
+	//
+	// 默认30s
+	PollInterval time.Duration
+	// 超过该时间没有收到数据视为连接中断
+	//
+	// 默认30s
+	StallTimeout time.Duration
+	// 录制过一场直播后，下播时停止录制
+	//
+	// 默认false，一直等待下一次开播
+	StopOnEnd bool
+}
+
+// LiveRecorder 直播录制，下播后等待下一次开播，断流后自动重新获取直播流并续写当前文件
+type LiveRecorder struct {
+	RoomID  int64 // 真实直播间ID
+	ShortID int   // 直播间短号 没有时为0
+	UID     int64 // 主播mid
+
+	setting *LiveRecordSetting
+	conn    *LiveConn
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+
+	mu    sync.Mutex // 保护files与file
+	files []string
+	file  *liveRecordFile
+
+	// 以下仅在录制goroutine中使用
+	session time.Time // 本场录制的开始时间 未在录制时为零值
+	part    int
+	flv     liveFLVState
+	hls     liveHLSState
+}
+
+type liveRecordFile struct {
+	path    string
+	start   time.Time
+	danmaku []*Danmaku
+	flv     *flvFile
+	raw     *os.File // HLS分片直接拼接写入
+}
+
+// liveFLVState 跨连接保持的FLV状态，用于修正断流重连后的时间戳
+type liveFLVState struct {
+	meta    *amfObject
+	video   *flvTag // 视频序列头
+	audio   *flvTag // 音频序列头
+	delta   int64   // 输出时间戳 = 输入时间戳 - delta
+	lastIn  int64
+	lastOut int64
+	started bool
+	resync  bool // 新的连接，下一个tag重新计算delta
+}
+
+type liveHLSState struct {
+	mapKey string
+	init   []byte
+	size   int64
+	dur    time.Duration
+	seen   map[string]bool
+	order  []string
+}
+
+// liveRecordFileError 文件读写错误，无法通过重试恢复
+type liveRecordFileError struct {
+	err error
+}
+
+func (e *liveRecordFileError) Error() string {
+	return e.err.Error()
+}
+
+// NewLiveRecorder 开始录制直播间，未开播时等待开播
+//
+// roomID: 可为短号也可以是真实房号
+//
+// 获取直播间信息或连接弹幕失败时直接返回错误
+func NewLiveRecorder(roomID int64, setting *LiveRecordSetting) (*LiveRecorder, error) {
+	s := LiveRecordSetting{}
+	if setting != nil {
+		s = *setting
+	}
+	if s.Comm == nil {
+		s.Comm = NewCommClient(&CommSetting{})
+	}
+	if s.Qn <= 0 {
+		s.Qn = 10000
+	}
+	if s.Format == "" {
+		s.Format = LiveRecordFLV
+	}
+	if s.Format != LiveRecordFLV && s.Format != LiveRecordHLS {
+		return nil, errors.Errorf("unsupported format: %s", s.Format)
+	}
+	if s.Dir == "" {
+		s.Dir = "."
+	}
+	if s.FileName == nil {
+		s.FileName = func(roomID int64, start time.Time, part int) string {
+			return fmt.Sprintf("%d_%s_%d", roomID, start.Format("20060102_150405"), part)
+		}
+	}
+	if s.RetryInterval <= 0 {
+		s.RetryInterval = 5 * time.Second
+	}
+	if s.PollInterval <= 0 {
+		s.PollInterval = 30 * time.Second
+	}
+	if s.StallTimeout <= 0 {
+		s.StallTimeout = 30 * time.Second
+	}
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	info, err := s.Comm.LiveGetRoomInfoByID(roomID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get room info")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &LiveRecorder{
+		RoomID:  info.RoomID,
+		ShortID: info.ShortID,
+		UID:     info.UID,
+		setting: &s,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	if s.Danmaku {
+		cs := LiveConnSetting{}
+		if s.LiveConn != nil {
+			cs = *s.LiveConn
+		}
+		if cs.Comm == nil {
+			cs.Comm = s.Comm
+		}
+		h := &LiveHandler{OnDanmaku: r.onDanmaku}
+		next := cs.Handler
+		cs.Handler = func(msg *LiveMsg) {
+			h.Handle(msg)
+			if next != nil {
+				next(msg)
+			}
+		}
+		if r.conn, err = NewLiveConn(r.RoomID, &cs); err != nil {
+			cancel()
+			return nil, errors.Wrap(err, "connect danmaku")
+		}
+	}
+
+	go r.run()
+	return r, nil
+}
+
+// Files 已录制完成的文件
+func (r *LiveRecorder) Files() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.files...)
+}
+
+// Done 录制停止时关闭
+func (r *LiveRecorder) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err 录制停止的原因，调用 Close 或 StopOnEnd 停止时为nil
+func (r *LiveRecorder) Err() error {
+	<-r.done
+	return r.err
+}
+
+// Close 停止录制，等待当前文件写入完成
+func (r *LiveRecorder) Close() error {
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+func (r *LiveRecorder) run() {
+	defer func() {
+		if err := r.closeFile(); err != nil && r.err == nil {
+			r.err = err
+		}
+		if r.conn != nil {
+			_ = r.conn.Close()
+		}
+		close(r.done)
+	}()
+	for r.ctx.Err() == nil {
+		info, err := r.setting.Comm.LiveGetPlayInfo(r.RoomID, r.setting.Qn)
+		if err != nil {
+			r.onError(errors.Wrap(err, "get play info"))
+			r.sleep(r.setting.RetryInterval)
+			continue
+		}
+		if info.LiveStatus != 1 {
+			if !r.session.IsZero() {
+				if err = r.endSession(); err != nil {
+					r.err = err
+					return
+				}
+				if r.setting.StopOnEnd {
+					return
+				}
+			}
+			r.sleep(r.setting.PollInterval)
+			continue
+		}
+		if r.session.IsZero() {
+			r.session = time.Now()
+		}
+		if err = r.record(info); err != nil {
+			if _, ok := errors.Cause(err).(*liveRecordFileError); ok {
+				r.err = err
+				return
+			}
+			if r.ctx.Err() == nil {
+				r.onError(err)
+				r.sleep(r.setting.RetryInterval)
+			}
+		}
+	}
+}
+
+func (r *LiveRecorder) onError(err error) {
+	if r.setting.OnError != nil {
+		r.setting.OnError(err)
+	}
+}
+
+func (r *LiveRecorder) sleep(d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-r.ctx.Done():
+	}
+}
+
+// endSession 下播，关闭文件并丢弃本场的流状态
+func (r *LiveRecorder) endSession() error {
+	err := r.closeFile()
+	r.session = time.Time{}
+	r.part = 0
+	r.flv = liveFLVState{}
+	r.hls = liveHLSState{}
+	return err
+}
+
+// record 依次尝试各个CDN，收到数据后断开视为断流，返回nil以立即重新获取直播流
+func (r *LiveRecorder) record(info *LivePlayInfo) error {
+	codec, ext, err := selectLiveStream(info, r.setting.Format)
+	if err != nil {
+		return err
+	}
+	expire := time.Time{}
+	if len(codec.URLInfo) > 0 && codec.URLInfo[0].StreamTTL > 0 {
+		expire = time.Now().Add(time.Duration(codec.URLInfo[0].StreamTTL) * time.Second)
+	}
+	var last error
+	for _, link := range codec.URLs() {
+		var n int
+		if r.setting.Format == LiveRecordHLS {
+			n, err = r.recordHLS(link, ext, expire)
+		} else {
+			n, err = r.recordFLV(link)
+		}
+		if r.ctx.Err() != nil {
+			return nil
+		}
+		if _, ok := errors.Cause(err).(*liveRecordFileError); ok {
+			return err
+		}
+		if n > 0 {
+			if err != nil && err != io.EOF && err != errLiveStreamExpired {
+				r.onError(err)
+			}
+			return nil
+		}
+		last = err
+	}
+	if last == nil {
+		last = errors.New("no stream url")
+	}
+	return last
+}
+
+// selectLiveStream 按格式选择直播流，优先AVC编码
+func selectLiveStream(info *LivePlayInfo, format string) (*LiveStreamCodec, string, error) {
+	type choice struct {
+		protocol, format, ext string
+	}
+	choices := []choice{{"http_stream", "flv", "flv"}}
+	if format == LiveRecordHLS {
+		choices = []choice{{"http_hls", "fmp4", "mp4"}, {"http_hls", "ts", "ts"}}
+	}
+	if info.PlayURLInfo == nil || info.PlayURLInfo.PlayURL == nil {
+		return nil, "", errors.New("missing playurl")
+	}
+	for _, c := range choices {
+		var found *LiveStreamCodec
+		for _, s := range info.PlayURLInfo.PlayURL.Stream {
+			if s.ProtocolName != c.protocol {
+				continue
+			}
+			for _, f := range s.Format {
+				if f.FormatName != c.format {
+					continue
+				}
+				for _, codec := range f.Codec {
+					if len(codec.URLInfo) == 0 {
+						continue
+					}
+					if codec.CodecName == "avc" {
+						return codec, c.ext, nil
+					}
+					if found == nil {
+						found = codec
+					}
+				}
+			}
+		}
+		if found != nil {
+			return found, c.ext, nil
+		}
+	}
+	return nil, "", errors.Errorf("no %s stream", format)
+}
+
+func (r *LiveRecorder) newRequest(ctx context.Context, link string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Origin", "https://live.bilibili.com")
+	req.Header.Add("Referer", "https://live.bilibili.com")
+	req.Header.Add("User-Agent", r.setting.Comm.ua)
+	return req, nil
+}
+
+// openStream 打开直播流，超过 StallTimeout 没有数据时关闭连接
+func (r *LiveRecorder) openStream(link string) (io.ReadCloser, error) {
+	req, err := r.newRequest(r.ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.setting.Comm.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("%s: %s", resp.Status, link)
+	}
+	s := &stallReader{rc: resp.Body, d: r.setting.StallTimeout}
+	s.timer = time.AfterFunc(s.d, func() { resp.Body.Close() })
+	return s, nil
+}
+
+// fetch 下载m3u8与分片
+func (r *LiveRecorder) fetch(link string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(r.ctx, r.setting.StallTimeout)
+	defer cancel()
+	req, err := r.newRequest(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.setting.Comm.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s: %s", resp.Status, link)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+type stallReader struct {
+	rc    io.ReadCloser
+	d     time.Duration
+	timer *time.Timer
+}
+
+func (s *stallReader) Read(p []byte) (int, error) {
+	n, err := s.rc.Read(p)
+	s.timer.Reset(s.d)
+	return n, err
+}
+
+func (s *stallReader) Close() error {
+	s.timer.Stop()
+	return s.rc.Close()
+}
+
+// recordFLV 返回收到的tag数量
+func (r *LiveRecorder) recordFLV(link string) (int, error) {
+	body, err := r.openStream(link)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	fr := newFLVReader(body)
+	r.flv.resync = true
+	n := 0
+	for {
+		tag, err := fr.next()
+		if err != nil {
+			return n, err
+		}
+		n++
+		if err = r.writeFLVTag(tag); err != nil {
+			return n, err
+		}
+	}
+}
+
+func (r *LiveRecorder) writeFLVTag(tag *flvTag) error {
+	s := &r.flv
+	if tag.Type == flvTagScript {
+		if m := parseFLVMeta(tag.Data); m != nil {
+			s.meta = m
+		}
+		return nil
+	}
+	if tag.Type != flvTagAudio && tag.Type != flvTagVideo {
+		return nil
+	}
+
+	// 修正时间戳：新连接或时间戳跳变时，从上一个输出的时间戳继续
+	ts := tag.Timestamp
+	if s.resync || !s.started || ts-s.lastIn < -1000 || ts-s.lastIn > 10000 {
+		s.delta = ts - s.lastOut
+		s.resync = false
+	}
+	s.lastIn = ts
+	out := ts - s.delta
+	if out < s.lastOut {
+		out = s.lastOut
+	}
+	s.lastOut = out
+	s.started = true
+	t := &flvTag{Type: tag.Type, Timestamp: out, Data: tag.Data}
+
+	if tag.isSequenceHeader() {
+		cur := &s.audio
+		if tag.Type == flvTagVideo {
+			cur = &s.video
+		}
+		if *cur != nil && bytes.Equal((*cur).Data, tag.Data) {
+			return nil
+		}
+		old := *cur
+		*cur = t
+		f := r.currentFile()
+		if f == nil {
+			return nil
+		}
+		// 编码参数变化，新文件开头会写入新的序列头
+		if old != nil {
+			return r.closeFile()
+		}
+		return wrapFileError(f.flv.write(t))
+	}
+
+	f := r.currentFile()
+	if f != nil && r.shouldSplit(f.flv.size, f.flv.durationAt(out)) && (tag.isKeyframe() || s.video == nil) {
+		if err := r.closeFile(); err != nil {
+			return err
+		}
+		f = nil
+	}
+	if f == nil {
+		var headers []*flvTag
+		for _, h := range []*flvTag{s.video, s.audio} {
+			if h != nil {
+				headers = append(headers, h)
+			}
+		}
+		path := r.nextPath("flv")
+		ff, err := createFLVFile(path, s.meta, headers)
+		if err != nil {
+			return wrapFileError(err)
+		}
+		f = r.openFile(&liveRecordFile{path: path, flv: ff})
+	}
+	return wrapFileError(f.flv.write(t))
+}
+
+// recordHLS 轮询m3u8并下载新的分片，返回下载的分片数量
+func (r *LiveRecorder) recordHLS(link, ext string, expire time.Time) (int, error) {
+	s := &r.hls
+	if s.seen == nil {
+		s.seen = make(map[string]bool)
+	}
+	n := 0
+	idle := time.Now()
+	for {
+		if !expire.IsZero() && time.Now().After(expire) {
+			return n, errLiveStreamExpired
+		}
+		base, err := url.Parse(link)
+		if err != nil {
+			return n, err
+		}
+		data, err := r.fetch(link)
+		if err != nil {
+			return n, err
+		}
+		p, err := parseHLSPlaylist(base, data)
+		if err != nil {
+			return n, err
+		}
+		if len(p.Variants) > 0 {
+			link = p.Variants[0]
+			continue
+		}
+
+		if p.Map != "" {
+			if key := hlsSegmentKey(p.Map); key != s.mapKey {
+				init, err := r.fetch(p.Map)
+				if err != nil {
+					return n, err
+				}
+				changed := s.init != nil && !bytes.Equal(init, s.init)
+				s.mapKey, s.init = key, init
+				if changed {
+					if err = r.closeFile(); err != nil {
+						return n, err
+					}
+				}
+			}
+		}
+
+		for _, seg := range p.Segments {
+			key := hlsSegmentKey(seg.URI)
+			if s.seen[key] {
+				continue
+			}
+			data, err := r.fetch(seg.URI)
+			if err != nil {
+				return n, err
+			}
+			s.markSeen(key)
+			n++
+			idle = time.Now()
+			if err = r.writeHLSSegment(data, seg.Duration, ext); err != nil {
+				return n, err
+			}
+		}
+		if p.End {
+			return n, io.EOF
+		}
+		if time.Since(idle) > r.setting.StallTimeout {
+			return n, errors.New("hls playlist stalled")
+		}
+
+		wait := p.TargetDuration / 2
+		if wait < time.Second {
+			wait = time.Second
+		}
+		select {
+		case <-time.After(wait):
+		case <-r.ctx.Done():
+			return n, r.ctx.Err()
+		}
+	}
+}
+
+// hlsSegmentKey 去掉会变化的url参数，用于识别重新获取直播流后重复的分片
+func hlsSegmentKey(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return u.Path
+}
+
+// markSeen 只保留最近的分片记录
+func (s *liveHLSState) markSeen(key string) {
+	s.seen[key] = true
+	s.order = append(s.order, key)
+	if len(s.order) > 128 {
+		delete(s.seen, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (r *LiveRecorder) writeHLSSegment(data []byte, dur time.Duration, ext string) error {
+	s := &r.hls
+	f := r.currentFile()
+	if f != nil && r.shouldSplit(s.size, s.dur) {
+		if err := r.closeFile(); err != nil {
+			return err
+		}
+		f = nil
+	}
+	if f == nil {
+		path := r.nextPath(ext)
+		raw, err := os.Create(path)
+		if err != nil {
+			return wrapFileError(err)
+		}
+		s.size, s.dur = 0, 0
+		f = r.openFile(&liveRecordFile{path: path, raw: raw})
+		// fMP4每个文件都以初始化分片开头
+		if s.init != nil {
+			if _, err = raw.Write(s.init); err != nil {
+				return wrapFileError(err)
+			}
+			s.size += int64(len(s.init))
+		}
+	}
+	if _, err := f.raw.Write(data); err != nil {
+		return wrapFileError(err)
+	}
+	s.size += int64(len(data))
+	s.dur += dur
+	return nil
+}
+
+func (r *LiveRecorder) shouldSplit(size int64, dur time.Duration) bool {
+	return r.setting.SplitSize > 0 && size >= r.setting.SplitSize ||
+		r.setting.SplitDuration > 0 && dur >= r.setting.SplitDuration
+}
+
+func (r *LiveRecorder) nextPath(ext string) string {
+	r.part++
+	return filepath.Join(r.setting.Dir, r.setting.FileName(r.RoomID, r.session, r.part)+"."+ext)
+}
+
+func (r *LiveRecorder) currentFile() *liveRecordFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file
+}
+
+func (r *LiveRecorder) openFile(f *liveRecordFile) *liveRecordFile {
+	f.start = time.Now()
+	r.mu.Lock()
+	r.file = f
+	r.mu.Unlock()
+	return f
+}
+
+// closeFile 关闭当前文件并写入弹幕
+func (r *LiveRecorder) closeFile() error {
+	r.mu.Lock()
+	f := r.file
+	r.file = nil
+	r.mu.Unlock()
+	if f == nil {
+		return nil
+	}
+
+	var err error
+	if f.flv != nil {
+		err = f.flv.close()
+	} else {
+		err = f.raw.Close()
+	}
+	if err != nil {
+		return wrapFileError(err)
+	}
+	if r.setting.Danmaku {
+		data, err := DanmakuToXML(r.RoomID, f.danmaku)
+		if err != nil {
+			return err
+		}
+		xmlPath := strings.TrimSuffix(f.path, filepath.Ext(f.path)) + ".xml"
+		if err = ioutil.WriteFile(xmlPath, data, 0644); err != nil {
+			return wrapFileError(err)
+		}
+	}
+
+	r.mu.Lock()
+	r.files = append(r.files, f.path)
+	r.mu.Unlock()
+	if r.setting.OnFile != nil {
+		r.setting.OnFile(f.path)
+	}
+	return nil
+}
+
+// onDanmaku 在弹幕连接的goroutine中调用
+func (r *LiveRecorder) onDanmaku(d *LiveDanmaku) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return
+	}
+	r.file.danmaku = append(r.file.danmaku, &Danmaku{
+		Progress: time.Since(r.file.start).Milliseconds(),
+		Mode:     d.Mode,
+		FontSize: d.FontSize,
+		Color:    int(d.Color),
+		MidHash:  MidHash(d.UID),
+		Content:  d.Content,
+		Ctime:    d.Timestamp / 1000,
+		Attr:     2,
+	})
+}
+
+func wrapFileError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &liveRecordFileError{err: err}
+}
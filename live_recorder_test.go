@@ -0,0 +1,302 @@
+package biligo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/iyear/biligo/livetest"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testFLVStream n组音视频tag，每10组一个关键帧，间隔100ms
+func testFLVStream(start int64, n int) [][]byte {
+	meta := &amfObject{ECMA: true, Props: []*amfProp{{Key: "width", Value: float64(1280)}}}
+	tags := [][]byte{
+		flvHeader,
+		(&flvTag{Type: flvTagScript, Data: amfEncode("onMetaData", meta)}).encode(),
+		(&flvTag{Type: flvTagVideo, Timestamp: start, Data: []byte{0x17, 0, 0, 0, 0, 1}}).encode(),
+		(&flvTag{Type: flvTagAudio, Timestamp: start, Data: []byte{0xaf, 0, 0x12, 0x10}}).encode(),
+	}
+	for i := 0; i < n; i++ {
+		frame := byte(0x27)
+		if i%10 == 0 {
+			frame = 0x17
+		}
+		ts := start + int64(i)*100
+		tags = append(tags,
+			(&flvTag{Type: flvTagVideo, Timestamp: ts, Data: []byte{frame, 1, 0, 0, 0, byte(i)}}).encode(),
+			(&flvTag{Type: flvTagAudio, Timestamp: ts, Data: []byte{0xaf, 1, byte(i)}}).encode(),
+		)
+	}
+	return tags
+}
+
+// handleTestPlayInfo 前live次请求返回直播中
+func handleTestPlayInfo(s *livetest.Server, live int, protocol, format, base string) {
+	var (
+		mu sync.Mutex
+		n  int
+	)
+	s.Handle("xlive/web-room/v2/index/getRoomPlayInfo", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n++
+		status := 0
+		if n <= live {
+			status = 1
+		}
+		mu.Unlock()
+		livetest.WriteData(w, map[string]interface{}{
+			"room_id":     s.RoomID,
+			"uid":         s.UID,
+			"live_status": status,
+			"playurl_info": map[string]interface{}{"playurl": map[string]interface{}{
+				"stream": []interface{}{map[string]interface{}{
+					"protocol_name": protocol,
+					"format": []interface{}{map[string]interface{}{
+						"format_name": format,
+						"codec": []interface{}{map[string]interface{}{
+							"codec_name": "avc",
+							"current_qn": 10000,
+							"base_url":   base,
+							"url_info": []interface{}{
+								map[string]interface{}{"host": "https://dead.example.com", "extra": "?dead=1"},
+								map[string]interface{}{"host": "https://cn.example.com", "extra": "?expires=1", "stream_ttl": 3600},
+							},
+						}},
+					}},
+				}},
+			}},
+		})
+	})
+}
+
+func newTestLiveRecorder(t *testing.T, s *livetest.Server, setting *LiveRecordSetting) *LiveRecorder {
+	setting.Comm = NewCommClient(&CommSetting{Client: s.Client()})
+	setting.LiveConn = &LiveConnSetting{Dialer: s.Dialer(), RetryInterval: 10 * time.Millisecond}
+	setting.Dir = t.TempDir()
+	setting.RetryInterval = 10 * time.Millisecond
+	setting.PollInterval = 10 * time.Millisecond
+	setting.StopOnEnd = true
+	setting.OnError = func(err error) { t.Log(err) }
+	r, err := NewLiveRecorder(s.RoomID, setting)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	select {
+	case <-r.Done():
+	case <-time.After(10 * time.Second):
+		r.Close()
+		t.Error("record timeout")
+		t.FailNow()
+	}
+	if err = r.Err(); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	return r
+}
+
+func readTestFLV(t *testing.T, path string) (*amfObject, []*flvTag) {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer f.Close()
+	var (
+		meta *amfObject
+		tags []*flvTag
+	)
+	fr := newFLVReader(f)
+	for {
+		tag, err := fr.next()
+		if err == io.EOF {
+			return meta, tags
+		}
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if tag.Type == flvTagScript {
+			meta = parseFLVMeta(tag.Data)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+}
+
+func TestNewLiveRecorder(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	handleTestPlayInfo(s, 2, "http_stream", "flv", "/live-bvc/1/live.flv")
+
+	var (
+		mu   sync.Mutex
+		conn int
+	)
+	s.Handle("live-bvc/1/live.flv", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dead") != "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		conn++
+		n := conn
+		mu.Unlock()
+		// 第二次连接时间戳从0开始
+		start := int64(1000)
+		if n > 1 {
+			start = 0
+		}
+		for i, tag := range testFLVStream(start, 20) {
+			_, _ = w.Write(tag)
+			if i == 10 && n == 1 {
+				w.(http.Flusher).Flush()
+				time.Sleep(100 * time.Millisecond)
+				_ = s.Send(`{"cmd":"DANMU_MSG","info":[[0,1,25,16777215,1650000000000],"录播测试",[1,"a"]]}`)
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+	})
+
+	var files []string
+	r := newTestLiveRecorder(t, s, &LiveRecordSetting{
+		Danmaku: true,
+		OnFile:  func(path string) { files = append(files, path) },
+	})
+	if fs := r.Files(); len(fs) != 1 || len(files) != 1 || fs[0] != files[0] || !strings.HasSuffix(fs[0], ".flv") {
+		t.Errorf("%v %v", fs, files)
+		t.FailNow()
+	}
+
+	meta, tags := readTestFLV(t, files[0])
+	if len(tags) != 2+20*2*2 {
+		t.Errorf("tags: %d", len(tags))
+	}
+	if !tags[0].isSequenceHeader() || !tags[1].isSequenceHeader() {
+		t.Error("missing sequence header")
+	}
+	last := int64(0)
+	for _, tag := range tags {
+		if tag.Timestamp < last {
+			t.Errorf("timestamp %d < %d", tag.Timestamp, last)
+			t.FailNow()
+		}
+		last = tag.Timestamp
+	}
+	if last != 3800 {
+		t.Errorf("last timestamp: %d", last)
+	}
+	if v, _ := meta.get("duration"); v != 3.8 {
+		t.Errorf("duration: %v", v)
+	}
+	info, _ := os.Stat(files[0])
+	if v, _ := meta.get("filesize"); v != float64(info.Size()) {
+		t.Errorf("filesize: %v %d", v, info.Size())
+	}
+	if v, _ := meta.get("width"); v != float64(1280) {
+		t.Errorf("width: %v", v)
+	}
+
+	data, err := ioutil.ReadFile(strings.TrimSuffix(files[0], ".flv") + ".xml")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	dm, err := ParseDanmakuXML(data)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(dm.Danmaku) != 1 || dm.Danmaku[0].Content != "录播测试" || dm.Danmaku[0].Ctime != 1650000000 || dm.Danmaku[0].MidHash != MidHash(1) {
+		t.Errorf("%+v", dm.Danmaku)
+	}
+}
+
+func TestLiveRecorderSplit(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	handleTestPlayInfo(s, 1, "http_stream", "flv", "/live-bvc/1/live.flv")
+	s.Handle("live-bvc/1/live.flv", func(w http.ResponseWriter, r *http.Request) {
+		for _, tag := range testFLVStream(0, 35) {
+			_, _ = w.Write(tag)
+		}
+	})
+
+	r := newTestLiveRecorder(t, s, &LiveRecordSetting{
+		SplitDuration: time.Second,
+		FileName: func(roomID int64, start time.Time, part int) string {
+			return fmt.Sprintf("%d-%d", roomID, part)
+		},
+	})
+	files := r.Files()
+	if len(files) != 4 || !strings.HasSuffix(files[3], "287083-4.flv") {
+		t.Errorf("%v", files)
+		t.FailNow()
+	}
+	for i, f := range files {
+		_, tags := readTestFLV(t, f)
+		// 每个文件都以序列头和关键帧开始
+		if len(tags) < 3 || !tags[0].isSequenceHeader() || !tags[1].isSequenceHeader() || !tags[2].isKeyframe() || tags[2].Timestamp != 0 {
+			t.Errorf("file %d: %+v", i, tags[:3])
+		}
+	}
+}
+
+func TestLiveRecorderHLS(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	handleTestPlayInfo(s, 1, "http_hls", "fmp4", "/live-bvc/1/index.m3u8")
+
+	var (
+		mu    sync.Mutex
+		fetch int
+	)
+	s.Handle("live-bvc/1/index.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dead") != "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		fetch++
+		n := fetch
+		mu.Unlock()
+		b := &strings.Builder{}
+		b.WriteString("#EXTM3U\n#EXT-X-MEDIA-SEQUENCE:1\n#EXT-X-TARGETDURATION:1\n#EXT-X-MAP:URI=\"h0.m4s\"\n")
+		for i := 1; i <= n+2; i++ {
+			fmt.Fprintf(b, "#EXTINF:1.000,\n%d.m4s?t=%d\n", i, n)
+		}
+		if n == 2 {
+			b.WriteString("#EXT-X-ENDLIST\n")
+		}
+		_, _ = w.Write([]byte(b.String()))
+	})
+	for _, name := range []string{"h0", "1", "2", "3", "4"} {
+		name := name
+		s.Handle("live-bvc/1/"+name+".m4s", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("[" + name + "]"))
+		})
+	}
+
+	r := newTestLiveRecorder(t, s, &LiveRecordSetting{Format: LiveRecordHLS})
+	files := r.Files()
+	if len(files) != 1 || !strings.HasSuffix(files[0], ".mp4") {
+		t.Errorf("%v", files)
+		t.FailNow()
+	}
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if !bytes.Equal(data, []byte("[h0][1][2][3][4]")) {
+		t.Errorf("%s", data)
+	}
+}
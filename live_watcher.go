@@ -0,0 +1,450 @@
+package biligo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LiveWatchEventType 直播间状态变化类型
+type LiveWatchEventType string
+
+const (
+	LiveWatchStart LiveWatchEventType = "start" // 开播
+	LiveWatchEnd   LiveWatchEventType = "end"   // 下播 包括转为轮播
+	LiveWatchTitle LiveWatchEventType = "title" // 修改标题
+	LiveWatchArea  LiveWatchEventType = "area"  // 修改分区
+)
+
+// LiveWatchEvent 直播间状态变化
+type LiveWatchEvent struct {
+	Type   LiveWatchEventType `json:"type"`
+	UID    int64              `json:"uid"`     // 主播mid
+	RoomID int64              `json:"room_id"` // 真实直播间ID
+	Time   time.Time          `json:"time"`    // 发现变化的时间，开播时间见 Status.LiveTime
+	Status *LiveRoomStatus    `json:"status"`  // 当前状态
+	Old    *LiveRoomStatus    `json:"old"`     // 上一次的状态
+}
+
+// LiveWatchSink 事件的输出，如 LiveWebhookSink
+type LiveWatchSink interface {
+	Send(e *LiveWatchEvent) error
+}
+
+// LiveWatchStore 保存直播间状态，重启后与新状态对比，不会漏掉停止期间的变化
+type LiveWatchStore interface {
+	Load() (map[int64]*LiveRoomStatus, error)
+	Save(status map[int64]*LiveRoomStatus) error
+}
+
+// LiveWatcherSetting 直播间状态监控配置，传入nil使用默认配置
+type LiveWatcherSetting struct {
+	// 默认为 NewCommClient(&CommSetting{})
+	Comm *CommClient
+	// 每轮检查的间隔
+	//
+	// 默认30s
+	Interval time.Duration
+	// 每次请求查询的主播数量
+	//
+	// 默认50
+	BatchSize int
+	// 两次请求之间的最小间隔，用于限制请求频率，同一轮的批次之间与相邻两轮之间都会遵守
+	//
+	// 默认1s
+	RequestInterval time.Duration
+	// 事件回调，在检查的goroutine中依次调用
+	//
+	// 为nil时事件写入 LiveWatcher.Events
+	Handler func(e *LiveWatchEvent)
+	// 事件同时发送到这些输出，失败时调用 OnError
+	Sinks []LiveWatchSink
+	// 状态保存位置，如 NewLiveWatchStateFile
+	//
+	// 默认不保存，首次检查时只记录状态不产生事件
+	Store LiveWatchStore
+	// 可恢复的错误，如请求失败、输出失败
+	OnError func(err error)
+}
+
+// LiveWatcher 定时批量检查主播的直播间状态，状态变化时产生事件
+type LiveWatcher struct {
+	setting *LiveWatcherSetting
+	events  chan *LiveWatchEvent
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	wake   chan struct{} // 添加主播后立即检查
+
+	last time.Time // 上一次请求的时间，只在检查的goroutine中使用
+
+	mu     sync.Mutex // 保护uids与status
+	uids   map[int64]bool
+	status map[int64]*LiveRoomStatus
+	saveMu sync.Mutex // 保证按顺序保存，避免旧快照覆盖新快照
+}
+
+// NewLiveWatcher 开始监控，之后通过 AddUID AddRoom 添加主播
+//
+// 读取 Store 失败时返回错误
+func NewLiveWatcher(setting *LiveWatcherSetting) (*LiveWatcher, error) {
+	s := LiveWatcherSetting{}
+	if setting != nil {
+		s = *setting
+	}
+	if s.Comm == nil {
+		s.Comm = NewCommClient(&CommSetting{})
+	}
+	if s.Interval <= 0 {
+		s.Interval = 30 * time.Second
+	}
+	if s.BatchSize <= 0 {
+		s.BatchSize = 50
+	}
+	if s.RequestInterval <= 0 {
+		s.RequestInterval = time.Second
+	}
+
+	status := make(map[int64]*LiveRoomStatus)
+	if s.Store != nil {
+		loaded, err := s.Store.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "load state")
+		}
+		for uid, st := range loaded {
+			status[uid] = st
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &LiveWatcher{
+		setting: &s,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		wake:    make(chan struct{}, 1),
+		uids:    make(map[int64]bool),
+		status:  status,
+	}
+	if s.Handler == nil {
+		w.events = make(chan *LiveWatchEvent, 64)
+	}
+	go w.run()
+	return w, nil
+}
+
+// AddUID 添加监控的主播mid，不等待检查间隔立即开始检查
+func (w *LiveWatcher) AddUID(uids ...int64) {
+	w.mu.Lock()
+	for _, uid := range uids {
+		w.uids[uid] = true
+	}
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// AddRoom 通过直播间ID添加监控，可为短号也可以是真实房号
+//
+// 返回对应的主播mid
+func (w *LiveWatcher) AddRoom(roomIDs ...int64) ([]int64, error) {
+	uids := make([]int64, 0, len(roomIDs))
+	for _, id := range roomIDs {
+		info, err := w.setting.Comm.LiveGetRoomInfoByID(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get room %d", id)
+		}
+		uids = append(uids, info.UID)
+	}
+	w.AddUID(uids...)
+	return uids, nil
+}
+
+// Remove 停止监控主播，同时删除记录与保存的状态
+func (w *LiveWatcher) Remove(uids ...int64) {
+	w.mu.Lock()
+	for _, uid := range uids {
+		delete(w.uids, uid)
+		delete(w.status, uid)
+	}
+	w.mu.Unlock()
+	w.save()
+}
+
+// Status 最近一次检查到的直播间状态，还未检查过时为nil
+func (w *LiveWatcher) Status(uid int64) *LiveRoomStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status[uid]
+}
+
+// Events 未设置 LiveWatcherSetting.Handler 时，事件从该channel读取，停止后关闭
+func (w *LiveWatcher) Events() <-chan *LiveWatchEvent {
+	return w.events
+}
+
+// Done 停止时关闭
+func (w *LiveWatcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// Close 停止监控，等待正在进行的检查结束
+func (w *LiveWatcher) Close() error {
+	w.cancel()
+	<-w.done
+	return nil
+}
+
+func (w *LiveWatcher) run() {
+	defer func() {
+		if w.events != nil {
+			close(w.events)
+		}
+		close(w.done)
+	}()
+	for {
+		w.check()
+		select {
+		case <-time.After(w.setting.Interval):
+		case <-w.wake:
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// check 检查一轮，按mid排序分批请求
+func (w *LiveWatcher) check() {
+	w.mu.Lock()
+	uids := make([]int64, 0, len(w.uids))
+	for uid := range w.uids {
+		uids = append(uids, uid)
+	}
+	w.mu.Unlock()
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	changed := false
+	// 停止时仍保存已产生事件的状态
+	defer func() {
+		if changed {
+			w.save()
+		}
+	}()
+	for i := 0; i < len(uids); i += w.setting.BatchSize {
+		// 频繁 AddUID 时也不会连续请求
+		if d := w.setting.RequestInterval - time.Since(w.last); !w.last.IsZero() && d > 0 {
+			select {
+			case <-time.After(d):
+			case <-w.ctx.Done():
+				return
+			}
+		}
+		end := i + w.setting.BatchSize
+		if end > len(uids) {
+			end = len(uids)
+		}
+		status, err := w.setting.Comm.LiveGetStatusByUIDs(uids[i:end])
+		w.last = time.Now()
+		if err != nil {
+			w.onError(errors.Wrap(err, "get live status"))
+			continue
+		}
+		now := time.Now()
+		for _, uid := range uids[i:end] {
+			st, ok := status[uid]
+			if !ok {
+				continue
+			}
+			events, updated := w.update(uid, st, now)
+			changed = changed || updated
+			for _, e := range events {
+				if !w.emit(e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *LiveWatcher) save() {
+	if w.setting.Store == nil {
+		return
+	}
+	w.saveMu.Lock()
+	defer w.saveMu.Unlock()
+	w.mu.Lock()
+	snapshot := make(map[int64]*LiveRoomStatus, len(w.status))
+	for uid, st := range w.status {
+		snapshot[uid] = st
+	}
+	w.mu.Unlock()
+	if err := w.setting.Store.Save(snapshot); err != nil {
+		w.onError(errors.Wrap(err, "save state"))
+	}
+}
+
+// update 记录新状态并与上一次对比，返回事件与需要保存的状态是否变化
+func (w *LiveWatcher) update(uid int64, st *LiveRoomStatus, now time.Time) ([]*LiveWatchEvent, bool) {
+	w.mu.Lock()
+	// 检查期间被移除
+	if !w.uids[uid] {
+		w.mu.Unlock()
+		return nil, false
+	}
+	old := w.status[uid]
+	w.status[uid] = st
+	w.mu.Unlock()
+
+	if old == nil {
+		return nil, true
+	}
+	var events []*LiveWatchEvent
+	add := func(tp LiveWatchEventType) {
+		events = append(events, &LiveWatchEvent{Type: tp, UID: uid, RoomID: st.RoomID, Time: now, Status: st, Old: old})
+	}
+	wasLive, isLive := old.LiveStatus == 1, st.LiveStatus == 1
+	switch {
+	case !wasLive && isLive:
+		add(LiveWatchStart)
+	case wasLive && !isLive:
+		add(LiveWatchEnd)
+	case wasLive && isLive && old.LiveTime != 0 && st.LiveTime != 0 && old.LiveTime != st.LiveTime:
+		// 两次检查之间下播后又开播
+		add(LiveWatchEnd)
+		add(LiveWatchStart)
+	}
+	if old.Title != st.Title {
+		add(LiveWatchTitle)
+	}
+	if old.AreaV2ID != st.AreaV2ID {
+		add(LiveWatchArea)
+	}
+	return events, len(events) > 0
+}
+
+func (w *LiveWatcher) emit(e *LiveWatchEvent) bool {
+	for _, sink := range w.setting.Sinks {
+		if err := sink.Send(e); err != nil {
+			w.onError(errors.Wrapf(err, "send %s event of %d", e.Type, e.UID))
+		}
+	}
+	if w.setting.Handler != nil {
+		w.setting.Handler(e)
+		return true
+	}
+	select {
+	case w.events <- e:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+func (w *LiveWatcher) onError(err error) {
+	if w.setting.OnError != nil {
+		w.setting.OnError(err)
+	}
+}
+
+// LiveWebhookSink 将事件以JSON POST到指定地址，响应状态码不为2xx时返回错误
+type LiveWebhookSink struct {
+	URL    string
+	Client *http.Client // 默认为 http.DefaultClient
+	Header http.Header  // 额外的请求头，如鉴权
+}
+
+// NewLiveWebhookSink client传入nil使用 http.DefaultClient
+func NewLiveWebhookSink(url string, client *http.Client) *LiveWebhookSink {
+	return &LiveWebhookSink{URL: url, Client: client}
+}
+
+func (s *LiveWebhookSink) Send(e *LiveWatchEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// LiveWatchStateFile 以JSON文件保存直播间状态
+type LiveWatchStateFile struct {
+	Path string
+}
+
+// NewLiveWatchStateFile 文件不存在时视为没有保存的状态
+func NewLiveWatchStateFile(path string) *LiveWatchStateFile {
+	return &LiveWatchStateFile{Path: path}
+}
+
+func (f *LiveWatchStateFile) Load() (map[int64]*LiveRoomStatus, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var status map[int64]*LiveRoomStatus
+	if err = json.Unmarshal(data, &status); err != nil {
+		return nil, errors.Wrapf(err, "parse %s", f.Path)
+	}
+	return status, nil
+}
+
+// Save 先写入临时文件再替换，避免写入中断导致文件损坏
+func (f *LiveWatchStateFile) Save(status map[int64]*LiveRoomStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = os.Rename(tmp.Name(), f.Path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package biligo
+
+import (
+	"encoding/json"
+	"github.com/iyear/biligo/livetest"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testLiveStatus struct {
+	mu      sync.Mutex
+	status  map[int64]*LiveRoomStatus
+	batches []int
+	times   []time.Time
+}
+
+func (s *testLiveStatus) set(uid int64, live int, title string, area int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status[uid] = &LiveRoomStatus{UID: uid, RoomID: uid * 10, LiveStatus: live, Title: title, AreaV2ID: area}
+}
+
+func (s *testLiveStatus) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uids := r.URL.Query()["uids[]"]
+	s.batches = append(s.batches, len(uids))
+	s.times = append(s.times, time.Now())
+	data := make(map[string]*LiveRoomStatus)
+	for _, u := range uids {
+		uid, _ := strconv.ParseInt(u, 10, 64)
+		if st, ok := s.status[uid]; ok {
+			data[u] = st
+		}
+	}
+	livetest.WriteData(w, data)
+}
+
+func recvLiveWatchEvent(t *testing.T, w *LiveWatcher) *LiveWatchEvent {
+	select {
+	case e := <-w.Events():
+		return e
+	case <-time.After(3 * time.Second):
+		t.Error("wait event timeout")
+		t.FailNow()
+	}
+	return nil
+}
+
+func TestNewLiveWatcher(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	st := &testLiveStatus{status: make(map[int64]*LiveRoomStatus)}
+	s.Handle("room/v1/Room/get_status_info_by_uids", st.serve)
+	st.set(1, 0, "a", 1)
+	st.set(2, 1, "b", 1)
+	st.set(3, 0, "c", 1)
+
+	var (
+		mu    sync.Mutex
+		hooks []*LiveWatchEvent
+	)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e LiveWatchEvent
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		hooks = append(hooks, &e)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	state := filepath.Join(t.TempDir(), "state.json")
+	setting := &LiveWatcherSetting{
+		Comm:            NewCommClient(&CommSetting{Client: s.Client()}),
+		Interval:        20 * time.Millisecond,
+		BatchSize:       2,
+		RequestInterval: time.Millisecond,
+		Sinks:           []LiveWatchSink{NewLiveWebhookSink(hook.URL, nil)},
+		Store:           NewLiveWatchStateFile(state),
+		OnError:         func(err error) { t.Error(err) },
+	}
+	w, err := NewLiveWatcher(setting)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	w.AddUID(1, 2, 3)
+	for w.Status(3) == nil {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	st.set(1, 1, "a", 1)
+	e := recvLiveWatchEvent(t, w)
+	if e.Type != LiveWatchStart || e.UID != 1 || e.RoomID != 10 || e.Old.LiveStatus != 0 || e.Time.IsZero() {
+		t.Errorf("%+v", e)
+	}
+	st.set(2, 2, "b2", 3)
+	for _, tp := range []LiveWatchEventType{LiveWatchEnd, LiveWatchTitle, LiveWatchArea} {
+		if e = recvLiveWatchEvent(t, w); e.Type != tp || e.UID != 2 {
+			t.Errorf("%+v", e)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Error(err)
+	}
+	if _, ok := <-w.Events(); ok {
+		t.Error("events not closed")
+	}
+
+	mu.Lock()
+	if len(hooks) != 4 || hooks[0].Type != LiveWatchStart || hooks[0].Status.UID != 1 {
+		t.Errorf("%+v", hooks)
+	}
+	mu.Unlock()
+	st.mu.Lock()
+	for _, n := range st.batches {
+		if n > 2 {
+			t.Errorf("batch size: %d", n)
+		}
+	}
+	st.mu.Unlock()
+
+	// 停止期间开播，重启后从状态文件恢复并产生事件
+	st.set(3, 1, "c", 1)
+	setting.Sinks = nil
+	if w, err = NewLiveWatcher(setting); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	defer w.Close()
+	if s := w.Status(1); s == nil || s.LiveStatus != 1 {
+		t.Errorf("%+v", s)
+	}
+	w.AddUID(1, 2, 3)
+	if e = recvLiveWatchEvent(t, w); e.Type != LiveWatchStart || e.UID != 3 {
+		t.Errorf("%+v", e)
+	}
+
+	// 移除后状态文件中也不再有该主播
+	w.Remove(3)
+	saved, err := NewLiveWatchStateFile(state).Load()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if _, ok := saved[3]; ok || saved[1] == nil {
+		t.Errorf("%+v", saved)
+	}
+}
+
+func TestLiveWatcherRequestInterval(t *testing.T) {
+	s := livetest.NewServer(287083)
+	defer s.Close()
+	st := &testLiveStatus{status: make(map[int64]*LiveRoomStatus)}
+	s.Handle("room/v1/Room/get_status_info_by_uids", st.serve)
+
+	const interval = 50 * time.Millisecond
+	w, err := NewLiveWatcher(&LiveWatcherSetting{
+		Comm:            NewCommClient(&CommSetting{Client: s.Client()}),
+		Interval:        time.Hour,
+		RequestInterval: interval,
+		OnError:         func(err error) { t.Error(err) },
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	// 每次添加都会唤醒检查，但请求之间仍保持间隔
+	for uid := int64(1); uid <= 5; uid++ {
+		w.AddUID(uid)
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(3 * interval)
+	if err = w.Close(); err != nil {
+		t.Error(err)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if len(st.times) < 2 {
+		t.Errorf("requests: %d", len(st.times))
+	}
+	for i := 1; i < len(st.times); i++ {
+		if d := st.times[i].Sub(st.times[i-1]); d < interval-5*time.Millisecond {
+			t.Errorf("request %d after %s", i, d)
+		}
+	}
+}
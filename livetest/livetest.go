@@ -0,0 +1,417 @@
+// Package livetest 本地模拟的直播间弹幕服务器，用于离线测试直播间客户端与消息处理
+//
+// Server 同时提供直播间信息、websocket服务器信息接口与websocket服务，
+// 使用 Server.Client 与 Server.Dialer 创建的客户端会将所有请求发往本地：
+//
+//	s := livetest.NewServer(287083)
+//	defer s.Close()
+//	conn, err := biligo.NewLiveConn(287083, &biligo.LiveConnSetting{
+//		Comm:   biligo.NewCommClient(&biligo.CommSetting{Client: s.Client()}),
+//		Dialer: s.Dialer(),
+//	})
+//	s.WaitClients(1, time.Second)
+//	s.SendCmd("DANMU_MSG", ...)
+package livetest
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"github.com/iyear/biligo/internal/liveproto"
+	"github.com/pkg/errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 消息的压缩方式，与B站协议版本一致
+const (
+	CompressNone   = liveproto.VerJSON
+	CompressZlib   = liveproto.VerZlib
+	CompressBrotli = liveproto.VerBrotli
+)
+
+// Auth 客户端发送的认证信息
+type Auth struct {
+	UID      int64  `json:"uid"`
+	RoomID   int64  `json:"roomid"`
+	Protover int    `json:"protover"`
+	Platform string `json:"platform"`
+	Type     int    `json:"type"`
+	Key      string `json:"key"`
+}
+
+// Server 模拟的直播间服务器
+type Server struct {
+	RoomID  int64  // 真实直播间ID
+	ShortID int    // 直播间短号 为0时没有短号
+	UID     int64  // 主播mid
+	Token   string // 认证使用的token
+
+	srv      *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu         sync.Mutex
+	handlers   map[string]http.HandlerFunc
+	conns      map[*conn]struct{}
+	auths      []*Auth
+	compress   uint16
+	popularity uint32
+	reject     bool
+	deadHosts  int
+}
+
+type conn struct {
+	mu sync.Mutex
+	ws *websocket.Conn
+}
+
+// NewServer 启动模拟服务器，使用完毕后需要调用 Close
+func NewServer(roomID int64) *Server {
+	s := &Server{
+		RoomID:   roomID,
+		UID:      1,
+		Token:    "livetest",
+		handlers: make(map[string]http.HandlerFunc),
+		conns:    make(map[*conn]struct{}),
+		compress: CompressBrotli,
+	}
+	s.srv = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close 断开所有连接并关闭服务器
+func (s *Server) Close() {
+	s.Disconnect()
+	s.srv.Close()
+}
+
+// URL 服务器地址 https://127.0.0.1:port
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Client 将所有请求发往本服务器的http client，用于创建 CommClient 等
+func (s *Server) Client() *http.Client {
+	u, _ := url.Parse(s.srv.URL)
+	return &http.Client{Transport: &rewriteTransport{host: u.Host, rt: s.srv.Client().Transport}}
+}
+
+// Dialer 信任本服务器证书的websocket dialer
+func (s *Server) Dialer() *websocket.Dialer {
+	return &websocket.Dialer{
+		TLSClientConfig:  s.srv.Client().Transport.(*http.Transport).TLSClientConfig.Clone(),
+		HandshakeTimeout: 5 * time.Second,
+	}
+}
+
+type rewriteTransport struct {
+	host string
+	rt   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "https"
+	req.URL.Host = t.host
+	return t.rt.RoundTrip(req)
+}
+
+// Handle 添加或替换HTTP接口，endpoint 不含域名，如 room/v1/Room/get_info
+//
+// 默认提供 xlive/web-room/v1/index/getRoomPlayInfo 与 room/v1/Danmu/getConf
+func (s *Server) Handle(endpoint string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers["/"+endpoint] = h
+}
+
+// HandleData 添加返回固定数据的HTTP接口，data 会作为响应的data字段
+func (s *Server) HandleData(endpoint string, data interface{}) {
+	s.Handle(endpoint, func(w http.ResponseWriter, r *http.Request) {
+		WriteData(w, data)
+	})
+}
+
+// WriteData 写入 {"code":0,"message":"0","data":data} 格式的响应
+func WriteData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    0,
+		"message": "0",
+		"data":    data,
+	})
+}
+
+// SetCompress 设置之后发送消息的压缩方式，默认 CompressBrotli
+func (s *Server) SetCompress(c uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compress = c
+}
+
+// SetPopularity 设置心跳回复中的人气值
+func (s *Server) SetPopularity(p uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.popularity = p
+}
+
+// RejectAuth 设置是否拒绝之后的认证，拒绝时回复错误码并断开连接
+func (s *Server) RejectAuth(reject bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reject = reject
+}
+
+// SetDeadHosts 在服务器列表前添加n个无法连接的地址，用于测试切换服务器
+func (s *Server) SetDeadHosts(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadHosts = n
+}
+
+// Auths 已收到的认证信息，包括被拒绝的
+func (s *Server) Auths() []*Auth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Auth(nil), s.auths...)
+}
+
+// Clients 当前已认证的连接数
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// WaitClients 等待已认证的连接数达到n
+func (s *Server) WaitClients(n int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for s.Clients() < n {
+		if time.Now().After(deadline) {
+			return errors.Errorf("wait clients: want %d,got %d", n, s.Clients())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return nil
+}
+
+// Disconnect 断开所有连接，模拟服务器断线，客户端重连后可以继续使用
+func (s *Server) Disconnect() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = make(map[*conn]struct{})
+	s.mu.Unlock()
+	for c := range conns {
+		_ = c.ws.Close()
+	}
+}
+
+// Send 向所有已认证的连接发送消息，多条消息会合并为一个压缩包发送，与B站的行为一致
+//
+// msg 可以是 []byte string json.RawMessage 形式的JSON，或其他可以被json序列化的值
+func (s *Server) Send(msgs ...interface{}) error {
+	var batch []byte
+	for _, m := range msgs {
+		var body []byte
+		switch v := m.(type) {
+		case []byte:
+			body = v
+		case json.RawMessage:
+			body = v
+		case string:
+			body = []byte(v)
+		default:
+			var err error
+			if body, err = json.Marshal(v); err != nil {
+				return err
+			}
+		}
+		p, err := liveproto.Encode(&liveproto.Packet{Ver: liveproto.VerJSON, Op: liveproto.OpMessage, Body: body})
+		if err != nil {
+			return err
+		}
+		batch = append(batch, p...)
+	}
+
+	s.mu.Lock()
+	compress := s.compress
+	conns := make([]*conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+	if len(conns) == 0 {
+		return errors.New("no client connected")
+	}
+
+	data := batch
+	if compress != CompressNone {
+		var err error
+		if data, err = liveproto.Encode(&liveproto.Packet{Ver: compress, Op: liveproto.OpMessage, Body: batch}); err != nil {
+			return err
+		}
+	}
+	for _, c := range conns {
+		if err := c.write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendCmd 发送 {"cmd":cmd,"data":data} 格式的消息
+func (s *Server) SendCmd(cmd string, data interface{}) error {
+	return s.Send(map[string]interface{}{"cmd": cmd, "data": data})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/sub" {
+		s.serveWS(w, r)
+		return
+	}
+	s.mu.Lock()
+	h, ok := s.handlers[r.URL.Path]
+	s.mu.Unlock()
+	if ok {
+		h(w, r)
+		return
+	}
+	switch r.URL.Path {
+	case "/xlive/web-room/v1/index/getRoomPlayInfo":
+		id, _ := strconv.ParseInt(r.URL.Query().Get("room_id"), 10, 64)
+		if id != s.RoomID && (s.ShortID == 0 || id != int64(s.ShortID)) {
+			writeError(w, 19002000, "获取初始化数据失败")
+			return
+		}
+		WriteData(w, map[string]interface{}{
+			"room_id":     s.RoomID,
+			"short_id":    s.ShortID,
+			"uid":         s.UID,
+			"live_status": 1,
+		})
+	case "/room/v1/Danmu/getConf":
+		WriteData(w, s.wsConf())
+	default:
+		writeError(w, -404, "啥都木有")
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "message": msg})
+}
+
+func (s *Server) wsConf() map[string]interface{} {
+	u, _ := url.Parse(s.srv.URL)
+	host, port, _ := net.SplitHostPort(u.Host)
+	p, _ := strconv.Atoi(port)
+
+	s.mu.Lock()
+	dead := s.deadHosts
+	s.mu.Unlock()
+
+	var hosts []map[string]interface{}
+	for i := 0; i < dead; i++ {
+		// 端口1通常无法连接
+		hosts = append(hosts, map[string]interface{}{"host": host, "port": 1, "wss_port": 1, "ws_port": 1})
+	}
+	hosts = append(hosts, map[string]interface{}{"host": host, "port": p, "wss_port": p, "ws_port": p})
+	return map[string]interface{}{
+		"host":             host,
+		"port":             p,
+		"host_server_list": hosts,
+		"token":            s.Token,
+	}
+}
+
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &conn{ws: ws}
+	defer ws.Close()
+
+	// 第一个包必须是认证包
+	_ = ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		return
+	}
+	packets, err := liveproto.Decode(data)
+	if err != nil || len(packets) == 0 || packets[0].Op != liveproto.OpAuth {
+		return
+	}
+	auth := &Auth{}
+	if err = json.Unmarshal(packets[0].Body, auth); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.auths = append(s.auths, auth)
+	ok := !s.reject && auth.RoomID == s.RoomID && auth.Key == s.Token
+	s.mu.Unlock()
+
+	code := 0
+	if !ok {
+		code = -101
+	}
+	if err = c.writePacket(liveproto.OpAuthReply, []byte(fmt.Sprintf(`{"code":%d}`, code))); err != nil || !ok {
+		return
+	}
+
+	s.mu.Lock()
+	s.conns[c] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+	}()
+
+	_ = ws.SetReadDeadline(time.Time{})
+	for {
+		_, data, err = ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		packets, err = liveproto.Decode(data)
+		if err != nil {
+			return
+		}
+		for _, p := range packets {
+			if p.Op != liveproto.OpHeartbeat {
+				continue
+			}
+			s.mu.Lock()
+			body := make([]byte, 4)
+			binary.BigEndian.PutUint32(body, s.popularity)
+			s.mu.Unlock()
+			if err = c.writePacket(liveproto.OpHeartbeatReply, body); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *conn) writePacket(op uint32, body []byte) error {
+	data, err := liveproto.Encode(&liveproto.Packet{Ver: liveproto.VerInt, Op: op, Seq: 1, Body: body})
+	if err != nil {
+		return err
+	}
+	return c.write(data)
+}
+
+func (c *conn) write(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.ws.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return c.ws.WriteMessage(websocket.BinaryMessage, data)
+}
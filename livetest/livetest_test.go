@@ -0,0 +1,46 @@
+package livetest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestServer_HandleData(t *testing.T) {
+	s := NewServer(1)
+	defer s.Close()
+	s.HandleData("room/v1/Room/get_info", map[string]interface{}{"title": "test"})
+
+	for endpoint, want := range map[string]int{
+		"room/v1/Room/get_info":                   0,
+		"room/v1/Danmu/getConf":                   0,
+		"xlive/web-room/v1/index/getRoomPlayInfo": 19002000,
+		"x/unknown": -404,
+	} {
+		resp, err := s.Client().Get("https://api.live.bilibili.com/" + endpoint)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		var r struct {
+			Code int `json:"code"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&r)
+		resp.Body.Close()
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		if r.Code != want {
+			t.Errorf("%s: want %d,got %d", endpoint, want, r.Code)
+			t.FailNow()
+		}
+	}
+}
+func TestServer_Send(t *testing.T) {
+	s := NewServer(1)
+	defer s.Close()
+	if err := s.SendCmd("LIVE", nil); err == nil {
+		t.Error("send without client should fail")
+		t.FailNow()
+	}
+}
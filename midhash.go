@@ -0,0 +1,132 @@
+package biligo
+
+import (
+	"github.com/pkg/errors"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// MidHash 计算mid对应的弹幕发送者hash 即 Danmaku.MidHash
+//
+// 为mid十进制字符串的CRC32(IEEE)，小写十六进制，不补前导0
+func MidHash(mid int64) string {
+	return strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(strconv.FormatInt(mid, 10)))), 16)
+}
+
+// CrackMidHash 由弹幕发送者hash反查mid，支持1至10位的mid
+//
+// 由于CRC32存在碰撞，可能返回多个候选mid，按从小到大排序，需要结合 CommClient.UserGetInfo 等信息判断
+//
+// 使用中间相遇：mid拆分为前缀与5位后缀，CRC寄存器对后缀的处理是仿射变换，
+// 可以由目标hash与后缀直接倒推出前缀处理后的寄存器值，再查前缀表。
+// 首次调用时构建约20万项的查找表(几十毫秒)，之后每次反查只需约50万次查表
+func CrackMidHash(hash string) ([]int64, error) {
+	h, err := strconv.ParseUint(hash, 16, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid mid hash: %s", hash)
+	}
+	midHashOnce.Do(initMidHashTable)
+
+	// 去除CRC32的结果取反，得到处理完所有字节后的寄存器值
+	reg := ^uint32(h)
+
+	var mids []int64
+	// 5位及以下的mid直接查前缀表
+	for n := 1; n <= midHashSuffixLen; n++ {
+		for _, p := range midHashPrefix[n][reg] {
+			mids = append(mids, int64(p))
+		}
+	}
+	// 6至10位的mid 枚举5位后缀(可以有前导0)，倒推前缀寄存器
+	target := midHashUnshift(reg)
+	for s, g := range midHashSuffix {
+		want := target ^ g
+		for n := 1; n <= midHashSuffixLen; n++ {
+			for _, p := range midHashPrefix[n][want] {
+				mids = append(mids, int64(p)*midHashSuffixMod+int64(s))
+			}
+		}
+	}
+	sort.Slice(mids, func(i, j int) bool {
+		return mids[i] < mids[j]
+	})
+	return mids, nil
+}
+
+const (
+	midHashSuffixLen = 5
+	midHashSuffixMod = 100000
+)
+
+var (
+	midHashOnce sync.Once
+	// midHashPrefix[n] n位数字(无前导0)从初始寄存器处理后的寄存器值 -> 数字
+	midHashPrefix [midHashSuffixLen + 1]map[uint32][]uint32
+	// midHashSuffix[s] 5位后缀s(有前导0)从0寄存器处理后的寄存器值，再经过5次零字节逆变换
+	midHashSuffix []uint32
+	// midHashInv 零字节变换的逆查表 crc表项的最高字节 -> 表下标
+	midHashInv [256]byte
+)
+
+func initMidHashTable() {
+	for i, v := range crc32.IEEETable {
+		midHashInv[v>>24] = byte(i)
+	}
+
+	// 按位数逐层扩展前缀，复用上一层的寄存器值
+	type node struct {
+		num uint32
+		reg uint32
+	}
+	layer := []node{{0, ^uint32(0)}}
+	for n := 1; n <= midHashSuffixLen; n++ {
+		next := make([]node, 0, len(layer)*10)
+		m := make(map[uint32][]uint32, len(layer)*10)
+		for _, p := range layer {
+			for d := uint32(0); d <= 9; d++ {
+				// 首位不能为0
+				if n == 1 && d == 0 {
+					continue
+				}
+				c := node{num: p.num*10 + d, reg: midHashStep(p.reg, byte('0'+d))}
+				next = append(next, c)
+				m[c.reg] = append(m[c.reg], c.num)
+			}
+		}
+		midHashPrefix[n] = m
+		layer = next
+	}
+
+	midHashSuffix = make([]uint32, midHashSuffixMod)
+	var digits [midHashSuffixLen]byte
+	for s := 0; s < midHashSuffixMod; s++ {
+		v := s
+		for i := midHashSuffixLen - 1; i >= 0; i-- {
+			digits[i] = byte('0' + v%10)
+			v /= 10
+		}
+		var reg uint32
+		for _, b := range digits {
+			reg = midHashStep(reg, b)
+		}
+		midHashSuffix[s] = midHashUnshift(reg)
+	}
+}
+
+// midHashStep 处理一个字节，寄存器不做取反
+func midHashStep(reg uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(reg)^b] ^ reg>>8
+}
+
+// midHashUnshift 对寄存器做5次零字节变换的逆变换
+//
+// 处理后缀S可以写为 A(reg) ^ G(S)，A为5次零字节变换，是线性的，因此 A⁻¹ 可以分别作用于两项
+func midHashUnshift(reg uint32) uint32 {
+	for i := 0; i < midHashSuffixLen; i++ {
+		idx := midHashInv[reg>>24]
+		reg = (reg^crc32.IEEETable[idx])<<8 | uint32(idx)
+	}
+	return reg
+}
@@ -0,0 +1,45 @@
+package biligo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMidHash(t *testing.T) {
+	// crc32("1") = 83dcefb7
+	if h := MidHash(1); h != "83dcefb7" {
+		t.Error(h)
+		t.FailNow()
+	}
+}
+func TestCrackMidHash(t *testing.T) {
+	for _, mid := range []int64{1, 9, 10, 12345, 99999, 100000, 123456, 2333333, 12345678, 208259, 1234567890, 3493116088, 9999999999} {
+		start := time.Now()
+		mids, err := CrackMidHash(MidHash(mid))
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+		found := false
+		for _, m := range mids {
+			if MidHash(m) != MidHash(mid) {
+				t.Errorf("mid: %d,wrong candidate: %d", mid, m)
+				t.FailNow()
+			}
+			if m == mid {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("mid: %d,candidates: %v", mid, mids)
+			t.FailNow()
+		}
+		t.Logf("mid: %d,candidates: %v,cost: %s", mid, mids, time.Since(start))
+	}
+}
+func TestCrackMidHash2(t *testing.T) {
+	if _, err := CrackMidHash("xyz"); err == nil {
+		t.Error("want error when hash is invalid")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,202 @@
+package biligo
+
+// Quality 视频清晰度代码 即取流参数中的qn
+//
+// 参考：https://github.com/SocialSisterYi/bilibili-API-collect/blob/master/video/videostream_url.md#qn%E8%A7%86%E9%A2%91%E6%B8%85%E6%99%B0%E5%BA%A6%E6%A0%87%E8%AF%86
+type Quality int
+
+const (
+	Quality240P        Quality = 6   // 240P 极速 仅mp4方式
+	Quality360P        Quality = 16  // 360P 流畅
+	Quality480P        Quality = 32  // 480P 清晰
+	Quality720P        Quality = 64  // 720P 高清 登录认证
+	Quality720P60      Quality = 74  // 720P60 高帧率 登录认证
+	Quality1080P       Quality = 80  // 1080P 高清 登录认证
+	Quality1080PPlus   Quality = 112 // 1080P+ 高码率 大会员认证
+	Quality1080P60     Quality = 116 // 1080P60 高帧率 大会员认证
+	Quality4K          Quality = 120 // 4K 超清 需要fnval包含128 大会员认证
+	QualityHDR         Quality = 125 // HDR 真彩色 需要fnval包含64 大会员认证
+	QualityDolbyVision Quality = 126 // 杜比视界 需要fnval包含512 大会员认证
+	Quality8K          Quality = 127 // 8K 超高清 需要fnval包含1024 大会员认证
+)
+
+// AudioQuality dash音频流的清晰度代码 即 VideoPlayURLDashMedia 中的ID
+type AudioQuality int
+
+const (
+	AudioQuality64K   AudioQuality = 30216 // 64K
+	AudioQuality132K  AudioQuality = 30232 // 132K
+	AudioQuality192K  AudioQuality = 30280 // 192K
+	AudioQualityDolby AudioQuality = 30250 // 杜比全景声
+	AudioQualityHiRes AudioQuality = 30251 // Hi-Res无损
+)
+
+// Codec 视频编码代码 即 VideoPlayURLDashMedia 中的Codecid
+type Codec int
+
+const (
+	CodecAVC  Codec = 7  // AVC编码 兼容性最好
+	CodecHEVC Codec = 12 // HEVC编码
+	CodecAV1  Codec = 13 // AV1编码
+)
+
+// FnvalFlag 视频流格式标识 即取流参数中的fnval 除FLV与MP4外均为二进制标志位，可以用 | 组合
+type FnvalFlag int
+
+const (
+	FnvalFLV         FnvalFlag = 0    // FLV格式 已下线，仅部分旧视频可用
+	FnvalMP4         FnvalFlag = 1    // MP4格式 仅240P 360P 与dash互斥
+	FnvalDash        FnvalFlag = 16   // DASH格式
+	FnvalHDR         FnvalFlag = 64   // 是否需求HDR视频 需要同时包含dash
+	Fnval4K          FnvalFlag = 128  // 是否需求4K视频
+	FnvalDolbyAudio  FnvalFlag = 256  // 是否需求杜比音频 需要同时包含dash
+	FnvalDolbyVision FnvalFlag = 512  // 是否需求杜比视界 需要同时包含dash
+	Fnval8K          FnvalFlag = 1024 // 是否需求8K视频 需要同时包含dash
+	FnvalAV1         FnvalFlag = 2048 // 是否需求AV1编码 需要同时包含dash
+)
+
+// FnvalAll dash下需求全部格式
+const FnvalAll = FnvalDash | FnvalHDR | Fnval4K | FnvalDolbyAudio | FnvalDolbyVision | Fnval8K | FnvalAV1
+
+// PlayURLParam 取流参数构造器，用于生成 VideoGetPlayURL 的qn与fnval
+//
+//	p := NewPlayURLParam(Quality4K).HDR().AV1()
+//	r, err := client.VideoGetPlayURL(aid, cid, p.Qn(), p.Fnval())
+type PlayURLParam struct {
+	qn    Quality
+	fnval FnvalFlag
+}
+
+// NewPlayURLParam 默认请求DASH格式
+//
+// qn 期望的清晰度，dash格式下会返回所有可用清晰度，该值影响不大
+func NewPlayURLParam(qn Quality) *PlayURLParam {
+	return &PlayURLParam{qn: qn, fnval: FnvalDash}
+}
+
+// Quality 设置清晰度
+func (p *PlayURLParam) Quality(qn Quality) *PlayURLParam {
+	p.qn = qn
+	return p
+}
+
+// FLV 使用FLV格式，会清除其他所有标识
+func (p *PlayURLParam) FLV() *PlayURLParam {
+	p.fnval = FnvalFLV
+	return p
+}
+
+// MP4 使用MP4格式，会清除其他所有标识
+func (p *PlayURLParam) MP4() *PlayURLParam {
+	p.fnval = FnvalMP4
+	return p
+}
+
+// HDR 需求HDR视频
+func (p *PlayURLParam) HDR() *PlayURLParam {
+	return p.dash(FnvalHDR)
+}
+
+// FourK 需求4K视频
+func (p *PlayURLParam) FourK() *PlayURLParam {
+	return p.dash(Fnval4K)
+}
+
+// DolbyAudio 需求杜比音频
+func (p *PlayURLParam) DolbyAudio() *PlayURLParam {
+	return p.dash(FnvalDolbyAudio)
+}
+
+// DolbyVision 需求杜比视界
+func (p *PlayURLParam) DolbyVision() *PlayURLParam {
+	return p.dash(FnvalDolbyVision)
+}
+
+// EightK 需求8K视频
+func (p *PlayURLParam) EightK() *PlayURLParam {
+	return p.dash(Fnval8K)
+}
+
+// AV1 需求AV1编码
+func (p *PlayURLParam) AV1() *PlayURLParam {
+	return p.dash(FnvalAV1)
+}
+
+// All 需求DASH下的全部格式
+func (p *PlayURLParam) All() *PlayURLParam {
+	return p.dash(FnvalAll)
+}
+
+// Qn 获取qn参数
+func (p *PlayURLParam) Qn() int {
+	return int(p.qn)
+}
+
+// Fnval 获取fnval参数
+func (p *PlayURLParam) Fnval() int {
+	return int(p.fnval)
+}
+
+// dash 除MP4 FLV外的标识都需要dash，且与MP4互斥
+func (p *PlayURLParam) dash(f FnvalFlag) *PlayURLParam {
+	p.fnval = p.fnval&^FnvalMP4 | FnvalDash | f
+	return p
+}
+
+// BestVideo 从dash视频流中挑选最佳的一条，没有dash信息或没有符合条件的流时返回nil
+//
+// prefs 可接受的编码，越靠前越优先。先选清晰度最高的流，同一清晰度下按prefs顺序选择编码
+//
+// 不传入prefs时接受全部编码，优先级为 CodecAVC CodecHEVC CodecAV1
+func (r *VideoPlayURLResult) BestVideo(prefs ...Codec) *VideoPlayURLDashMedia {
+	if r.Dash == nil {
+		return nil
+	}
+	if len(prefs) == 0 {
+		prefs = []Codec{CodecAVC, CodecHEVC, CodecAV1}
+	}
+	rank := make(map[Codec]int, len(prefs))
+	for i, c := range prefs {
+		if _, ok := rank[c]; !ok {
+			rank[c] = i
+		}
+	}
+
+	var best *VideoPlayURLDashMedia
+	for _, v := range r.Dash.Video {
+		cr, ok := rank[Codec(v.Codecid)]
+		if !ok {
+			continue
+		}
+		if best == nil ||
+			v.ID > best.ID ||
+			v.ID == best.ID && cr < rank[Codec(best.Codecid)] ||
+			v.ID == best.ID && cr == rank[Codec(best.Codecid)] && v.Bandwidth > best.Bandwidth {
+			best = v
+		}
+	}
+	return best
+}
+
+// BestAudio 从dash音频流中挑选最佳的一条，没有dash信息或没有音频流时返回nil
+//
+// 优先级为 Hi-Res无损 > 杜比全景声 > 普通音频中清晰度最高的流
+func (r *VideoPlayURLResult) BestAudio() *VideoPlayURLDashMedia {
+	if r.Dash == nil {
+		return nil
+	}
+	if r.Dash.Flac != nil && r.Dash.Flac.Audio != nil {
+		return r.Dash.Flac.Audio
+	}
+	if r.Dash.Dolby != nil && len(r.Dash.Dolby.Audio) > 0 {
+		return r.Dash.Dolby.Audio[0]
+	}
+
+	var best *VideoPlayURLDashMedia
+	for _, a := range r.Dash.Audio {
+		if best == nil || a.ID > best.ID || a.ID == best.ID && a.Bandwidth > best.Bandwidth {
+			best = a
+		}
+	}
+	return best
+}
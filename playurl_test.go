@@ -0,0 +1,65 @@
+package biligo
+
+import "testing"
+
+func TestPlayURLParam(t *testing.T) {
+	p := NewPlayURLParam(Quality4K).HDR().AV1()
+	if p.Qn() != 120 || p.Fnval() != 16|64|2048 {
+		t.Errorf("qn: %d,fnval: %d", p.Qn(), p.Fnval())
+		t.FailNow()
+	}
+	if p.MP4().Fnval() != 1 {
+		t.Error(p.Fnval())
+		t.FailNow()
+	}
+	// 从MP4切换回dash
+	if p.Quality(Quality8K).EightK().Fnval() != 16|1024 {
+		t.Error(p.Fnval())
+		t.FailNow()
+	}
+	if f := NewPlayURLParam(Quality1080P).FLV().DolbyAudio().Fnval(); f != 16|256 {
+		t.Error(f)
+		t.FailNow()
+	}
+	if f := NewPlayURLParam(Quality1080P).All().Fnval(); f != 4048 {
+		t.Error(f)
+		t.FailNow()
+	}
+}
+func TestVideoPlayURLResult_BestVideo(t *testing.T) {
+	r := newTestPlayURLResult()
+	if v := r.BestVideo(); v.ID != 80 || v.Codecid != 7 {
+		t.Errorf("id: %d,codec: %d", v.ID, v.Codecid)
+		t.FailNow()
+	}
+	if v := r.BestVideo(CodecHEVC, CodecAVC); v.ID != 80 || v.Codecid != 12 {
+		t.Errorf("id: %d,codec: %d", v.ID, v.Codecid)
+		t.FailNow()
+	}
+	if v := r.BestVideo(CodecAV1); v != nil {
+		t.Errorf("id: %d,codec: %d", v.ID, v.Codecid)
+		t.FailNow()
+	}
+	if v := (&VideoPlayURLResult{}).BestVideo(); v != nil {
+		t.Errorf("id: %d,codec: %d", v.ID, v.Codecid)
+		t.FailNow()
+	}
+}
+func TestVideoPlayURLResult_BestAudio(t *testing.T) {
+	r := newTestPlayURLResult()
+	r.Dash.Audio = append(r.Dash.Audio, &VideoPlayURLDashMedia{ID: 30216, Bandwidth: 67000})
+	if a := r.BestAudio(); AudioQuality(a.ID) != AudioQuality192K {
+		t.Error(a.ID)
+		t.FailNow()
+	}
+	r.Dash.Dolby = &VideoPlayURLDashDolby{Type: 1, Audio: []*VideoPlayURLDashMedia{{ID: 30250, Codecs: "ec-3"}}}
+	if a := r.BestAudio(); AudioQuality(a.ID) != AudioQualityDolby {
+		t.Error(a.ID)
+		t.FailNow()
+	}
+	r.Dash.Flac = &VideoPlayURLDashFlac{Display: true, Audio: &VideoPlayURLDashMedia{ID: 30251, Codecs: "fLaC"}}
+	if a := r.BestAudio(); AudioQuality(a.ID) != AudioQualityHiRes {
+		t.Error(a.ID)
+		t.FailNow()
+	}
+}
@@ -352,6 +352,619 @@ func (x *DanmakuElem) GetAttr() int32 {
 	return 0
 }
 
+// 弹幕元数据 x/v2/dm/web/view
+type DmWebViewReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 是否已关闭弹幕
+	// 0:未关闭 1:已关闭
+	State    int32  `protobuf:"varint,1,opt,name=state,proto3" json:"state,omitempty"`
+	Text     string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	TextSide string `protobuf:"bytes,3,opt,name=text_side,json=textSide,proto3" json:"text_side,omitempty"`
+	// 分段弹幕配置
+	DmSge *DmSegConfig `protobuf:"bytes,4,opt,name=dm_sge,json=dmSge,proto3" json:"dm_sge,omitempty"`
+	// 云屏蔽配置
+	Flag *DanmakuFlagConfig `protobuf:"bytes,5,opt,name=flag,proto3" json:"flag,omitempty"`
+	// 高级弹幕专包url(bfs)
+	SpecialDms []string `protobuf:"bytes,6,rep,name=special_dms,json=specialDms,proto3" json:"special_dms,omitempty"`
+	// check box 是否展示
+	CheckBox bool `protobuf:"varint,7,opt,name=check_box,json=checkBox,proto3" json:"check_box,omitempty"`
+	// 弹幕数
+	Count int64 `protobuf:"varint,8,opt,name=count,proto3" json:"count,omitempty"`
+	// 互动弹幕
+	CommandDms []*CommandDm `protobuf:"bytes,9,rep,name=commandDms,proto3" json:"commandDms,omitempty"`
+	// 用户弹幕配置
+	DmSetting *DanmuWebPlayerConfig `protobuf:"bytes,10,opt,name=dm_setting,json=dmSetting,proto3" json:"dm_setting,omitempty"`
+	// 用户举报弹幕 cid维度屏蔽
+	ReportFilter []string `protobuf:"bytes,11,rep,name=report_filter,json=reportFilter,proto3" json:"report_filter,omitempty"`
+}
+
+func (x *DmWebViewReply) Reset() {
+	*x = DmWebViewReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dm_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DmWebViewReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmWebViewReply) ProtoMessage() {}
+
+func (x *DmWebViewReply) ProtoReflect() protoreflect.Message {
+	mi := &file_dm_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmWebViewReply.ProtoReflect.Descriptor instead.
+func (*DmWebViewReply) Descriptor() ([]byte, []int) {
+	return file_dm_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DmWebViewReply) GetState() int32 {
+	if x != nil {
+		return x.State
+	}
+	return 0
+}
+
+func (x *DmWebViewReply) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DmWebViewReply) GetTextSide() string {
+	if x != nil {
+		return x.TextSide
+	}
+	return ""
+}
+
+func (x *DmWebViewReply) GetDmSge() *DmSegConfig {
+	if x != nil {
+		return x.DmSge
+	}
+	return nil
+}
+
+func (x *DmWebViewReply) GetFlag() *DanmakuFlagConfig {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+func (x *DmWebViewReply) GetSpecialDms() []string {
+	if x != nil {
+		return x.SpecialDms
+	}
+	return nil
+}
+
+func (x *DmWebViewReply) GetCheckBox() bool {
+	if x != nil {
+		return x.CheckBox
+	}
+	return false
+}
+
+func (x *DmWebViewReply) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DmWebViewReply) GetCommandDms() []*CommandDm {
+	if x != nil {
+		return x.CommandDms
+	}
+	return nil
+}
+
+func (x *DmWebViewReply) GetDmSetting() *DanmuWebPlayerConfig {
+	if x != nil {
+		return x.DmSetting
+	}
+	return nil
+}
+
+func (x *DmWebViewReply) GetReportFilter() []string {
+	if x != nil {
+		return x.ReportFilter
+	}
+	return nil
+}
+
+// 分段弹幕配置
+type DmSegConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 分段时间(单位ms)
+	PageSize int64 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// 最大分页数
+	Total int64 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+}
+
+func (x *DmSegConfig) Reset() {
+	*x = DmSegConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dm_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DmSegConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmSegConfig) ProtoMessage() {}
+
+func (x *DmSegConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_dm_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmSegConfig.ProtoReflect.Descriptor instead.
+func (*DmSegConfig) Descriptor() ([]byte, []int) {
+	return file_dm_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DmSegConfig) GetPageSize() int64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *DmSegConfig) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// 云屏蔽配置
+type DanmakuFlagConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 云屏蔽等级
+	RecFlag int32 `protobuf:"varint,1,opt,name=rec_flag,json=recFlag,proto3" json:"rec_flag,omitempty"`
+	// 云屏蔽文案
+	RecText string `protobuf:"bytes,2,opt,name=rec_text,json=recText,proto3" json:"rec_text,omitempty"`
+	// 云屏蔽开关
+	RecSwitch int32 `protobuf:"varint,3,opt,name=rec_switch,json=recSwitch,proto3" json:"rec_switch,omitempty"`
+}
+
+func (x *DanmakuFlagConfig) Reset() {
+	*x = DanmakuFlagConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dm_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DanmakuFlagConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DanmakuFlagConfig) ProtoMessage() {}
+
+func (x *DanmakuFlagConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_dm_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DanmakuFlagConfig.ProtoReflect.Descriptor instead.
+func (*DanmakuFlagConfig) Descriptor() ([]byte, []int) {
+	return file_dm_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DanmakuFlagConfig) GetRecFlag() int32 {
+	if x != nil {
+		return x.RecFlag
+	}
+	return 0
+}
+
+func (x *DanmakuFlagConfig) GetRecText() string {
+	if x != nil {
+		return x.RecText
+	}
+	return ""
+}
+
+func (x *DanmakuFlagConfig) GetRecSwitch() int32 {
+	if x != nil {
+		return x.RecSwitch
+	}
+	return 0
+}
+
+// 互动弹幕条目
+type CommandDm struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 弹幕id
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 对象视频cid
+	Oid int64 `protobuf:"varint,2,opt,name=oid,proto3" json:"oid,omitempty"`
+	// 发送者mid
+	Mid string `protobuf:"bytes,3,opt,name=mid,proto3" json:"mid,omitempty"`
+	// 互动弹幕指令
+	Command string `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`
+	// 互动弹幕正文
+	Content string `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	// 出现时间(单位ms)
+	Progress int32 `protobuf:"varint,6,opt,name=progress,proto3" json:"progress,omitempty"`
+	// 创建时间
+	Ctime string `protobuf:"bytes,7,opt,name=ctime,proto3" json:"ctime,omitempty"`
+	// 发布时间
+	Mtime string `protobuf:"bytes,8,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	// 扩展json数据
+	Extra string `protobuf:"bytes,9,opt,name=extra,proto3" json:"extra,omitempty"`
+	// 弹幕id str类型
+	IdStr string `protobuf:"bytes,10,opt,name=idStr,proto3" json:"idStr,omitempty"`
+}
+
+func (x *CommandDm) Reset() {
+	*x = CommandDm{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dm_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandDm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandDm) ProtoMessage() {}
+
+func (x *CommandDm) ProtoReflect() protoreflect.Message {
+	mi := &file_dm_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandDm.ProtoReflect.Descriptor instead.
+func (*CommandDm) Descriptor() ([]byte, []int) {
+	return file_dm_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CommandDm) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CommandDm) GetOid() int64 {
+	if x != nil {
+		return x.Oid
+	}
+	return 0
+}
+
+func (x *CommandDm) GetMid() string {
+	if x != nil {
+		return x.Mid
+	}
+	return ""
+}
+
+func (x *CommandDm) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *CommandDm) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CommandDm) GetProgress() int32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *CommandDm) GetCtime() string {
+	if x != nil {
+		return x.Ctime
+	}
+	return ""
+}
+
+func (x *CommandDm) GetMtime() string {
+	if x != nil {
+		return x.Mtime
+	}
+	return ""
+}
+
+func (x *CommandDm) GetExtra() string {
+	if x != nil {
+		return x.Extra
+	}
+	return ""
+}
+
+func (x *CommandDm) GetIdStr() string {
+	if x != nil {
+		return x.IdStr
+	}
+	return ""
+}
+
+// 用户弹幕配置
+type DanmuWebPlayerConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 弹幕开关
+	DmSwitch bool `protobuf:"varint,1,opt,name=dm_switch,json=dmSwitch,proto3" json:"dm_switch,omitempty"`
+	// 智能云屏蔽
+	AiSwitch bool `protobuf:"varint,2,opt,name=ai_switch,json=aiSwitch,proto3" json:"ai_switch,omitempty"`
+	// 智能云屏蔽等级
+	AiLevel int32 `protobuf:"varint,3,opt,name=ai_level,json=aiLevel,proto3" json:"ai_level,omitempty"`
+	// 屏蔽类型-顶部
+	Blocktop bool `protobuf:"varint,4,opt,name=blocktop,proto3" json:"blocktop,omitempty"`
+	// 屏蔽类型-滚动
+	Blockscroll bool `protobuf:"varint,5,opt,name=blockscroll,proto3" json:"blockscroll,omitempty"`
+	// 屏蔽类型-底部
+	Blockbottom bool `protobuf:"varint,6,opt,name=blockbottom,proto3" json:"blockbottom,omitempty"`
+	// 屏蔽类型-彩色
+	Blockcolor bool `protobuf:"varint,7,opt,name=blockcolor,proto3" json:"blockcolor,omitempty"`
+	// 屏蔽类型-特殊
+	Blockspecial bool `protobuf:"varint,8,opt,name=blockspecial,proto3" json:"blockspecial,omitempty"`
+	// 防挡字幕
+	Preventshade bool `protobuf:"varint,9,opt,name=preventshade,proto3" json:"preventshade,omitempty"`
+	// 智能防挡弹幕(人像蒙版)
+	Dmask bool `protobuf:"varint,10,opt,name=dmask,proto3" json:"dmask,omitempty"`
+	// 弹幕不透明度
+	Opacity float32 `protobuf:"fixed32,11,opt,name=opacity,proto3" json:"opacity,omitempty"`
+	// 弹幕显示区域
+	Dmarea int32 `protobuf:"varint,12,opt,name=dmarea,proto3" json:"dmarea,omitempty"`
+	// 弹幕速度
+	Speedplus float32 `protobuf:"fixed32,13,opt,name=speedplus,proto3" json:"speedplus,omitempty"`
+	// 字体大小
+	Fontsize float32 `protobuf:"fixed32,14,opt,name=fontsize,proto3" json:"fontsize,omitempty"`
+	// 跟随屏幕缩放比例
+	Screensync bool `protobuf:"varint,15,opt,name=screensync,proto3" json:"screensync,omitempty"`
+	// 根据播放倍速调整速度
+	Speedsync bool `protobuf:"varint,16,opt,name=speedsync,proto3" json:"speedsync,omitempty"`
+	// 字体类型
+	Fontfamily string `protobuf:"bytes,17,opt,name=fontfamily,proto3" json:"fontfamily,omitempty"`
+	// 粗体
+	Bold bool `protobuf:"varint,18,opt,name=bold,proto3" json:"bold,omitempty"`
+	// 描边类型
+	Fontborder int32 `protobuf:"varint,19,opt,name=fontborder,proto3" json:"fontborder,omitempty"`
+	// 渲染类型
+	DrawType string `protobuf:"bytes,20,opt,name=draw_type,json=drawType,proto3" json:"draw_type,omitempty"`
+}
+
+func (x *DanmuWebPlayerConfig) Reset() {
+	*x = DanmuWebPlayerConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dm_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DanmuWebPlayerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DanmuWebPlayerConfig) ProtoMessage() {}
+
+func (x *DanmuWebPlayerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_dm_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DanmuWebPlayerConfig.ProtoReflect.Descriptor instead.
+func (*DanmuWebPlayerConfig) Descriptor() ([]byte, []int) {
+	return file_dm_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DanmuWebPlayerConfig) GetDmSwitch() bool {
+	if x != nil {
+		return x.DmSwitch
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetAiSwitch() bool {
+	if x != nil {
+		return x.AiSwitch
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetAiLevel() int32 {
+	if x != nil {
+		return x.AiLevel
+	}
+	return 0
+}
+
+func (x *DanmuWebPlayerConfig) GetBlocktop() bool {
+	if x != nil {
+		return x.Blocktop
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetBlockscroll() bool {
+	if x != nil {
+		return x.Blockscroll
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetBlockbottom() bool {
+	if x != nil {
+		return x.Blockbottom
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetBlockcolor() bool {
+	if x != nil {
+		return x.Blockcolor
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetBlockspecial() bool {
+	if x != nil {
+		return x.Blockspecial
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetPreventshade() bool {
+	if x != nil {
+		return x.Preventshade
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetDmask() bool {
+	if x != nil {
+		return x.Dmask
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetOpacity() float32 {
+	if x != nil {
+		return x.Opacity
+	}
+	return 0
+}
+
+func (x *DanmuWebPlayerConfig) GetDmarea() int32 {
+	if x != nil {
+		return x.Dmarea
+	}
+	return 0
+}
+
+func (x *DanmuWebPlayerConfig) GetSpeedplus() float32 {
+	if x != nil {
+		return x.Speedplus
+	}
+	return 0
+}
+
+func (x *DanmuWebPlayerConfig) GetFontsize() float32 {
+	if x != nil {
+		return x.Fontsize
+	}
+	return 0
+}
+
+func (x *DanmuWebPlayerConfig) GetScreensync() bool {
+	if x != nil {
+		return x.Screensync
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetSpeedsync() bool {
+	if x != nil {
+		return x.Speedsync
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetFontfamily() string {
+	if x != nil {
+		return x.Fontfamily
+	}
+	return ""
+}
+
+func (x *DanmuWebPlayerConfig) GetBold() bool {
+	if x != nil {
+		return x.Bold
+	}
+	return false
+}
+
+func (x *DanmuWebPlayerConfig) GetFontborder() int32 {
+	if x != nil {
+		return x.Fontborder
+	}
+	return 0
+}
+
+func (x *DanmuWebPlayerConfig) GetDrawType() string {
+	if x != nil {
+		return x.DrawType
+	}
+	return ""
+}
+
 var File_dm_proto protoreflect.FileDescriptor
 
 var file_dm_proto_rawDesc = []byte{
@@ -391,8 +1004,98 @@ var file_dm_proto_rawDesc = []byte{
 	0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
 	0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x64, 0x53, 0x74, 0x72, 0x18, 0x0c, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x64, 0x53, 0x74, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x74,
-	0x74, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x61, 0x74, 0x74, 0x72, 0x42, 0x05,
-	0x5a, 0x03, 0x2f, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x61, 0x74, 0x74, 0x72, 0x22, 0x9f,
+	0x03, 0x0a, 0x0e, 0x44, 0x6d, 0x57, 0x65, 0x62, 0x56, 0x69, 0x65, 0x77, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x74,
+	0x65, 0x78, 0x74, 0x5f, 0x73, 0x69, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x74, 0x65, 0x78, 0x74, 0x53, 0x69, 0x64, 0x65, 0x12, 0x2b, 0x0a, 0x06, 0x64, 0x6d, 0x5f, 0x73,
+	0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x61, 0x6e, 0x6d, 0x61,
+	0x6b, 0x75, 0x2e, 0x44, 0x6d, 0x53, 0x65, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05,
+	0x64, 0x6d, 0x53, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x61, 0x6e, 0x6d, 0x61, 0x6b, 0x75, 0x2e, 0x44, 0x61,
+	0x6e, 0x6d, 0x61, 0x6b, 0x75, 0x46, 0x6c, 0x61, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x04, 0x66, 0x6c, 0x61, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c,
+	0x5f, 0x64, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x70, 0x65, 0x63,
+	0x69, 0x61, 0x6c, 0x44, 0x6d, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f,
+	0x62, 0x6f, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x42, 0x6f, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x44, 0x6d, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x64, 0x61, 0x6e, 0x6d, 0x61, 0x6b, 0x75, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x44,
+	0x6d, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x44, 0x6d, 0x73, 0x12, 0x3c, 0x0a,
+	0x0a, 0x64, 0x6d, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x64, 0x61, 0x6e, 0x6d, 0x61, 0x6b, 0x75, 0x2e, 0x44, 0x61, 0x6e, 0x6d,
+	0x75, 0x57, 0x65, 0x62, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x52, 0x09, 0x64, 0x6d, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x23, 0x0a, 0x0d, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x0b, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x22, 0x40, 0x0a, 0x0b, 0x44, 0x6d, 0x53, 0x65, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x22, 0x68, 0x0a, 0x11, 0x44, 0x61, 0x6e, 0x6d, 0x61, 0x6b, 0x75, 0x46, 0x6c, 0x61,
+	0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x5f, 0x66,
+	0x6c, 0x61, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x65, 0x63, 0x46, 0x6c,
+	0x61, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x63, 0x54, 0x65, 0x78, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x72, 0x65, 0x63, 0x5f, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x72, 0x65, 0x63, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x22, 0xe7, 0x01, 0x0a,
+	0x09, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x44, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6f, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03,
+	0x6d, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x69, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x78,
+	0x74, 0x72, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x78, 0x74, 0x72, 0x61,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x64, 0x53, 0x74, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x69, 0x64, 0x53, 0x74, 0x72, 0x22, 0xe4, 0x04, 0x0a, 0x14, 0x44, 0x61, 0x6e, 0x6d, 0x75,
+	0x57, 0x65, 0x62, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x1b, 0x0a, 0x09, 0x64, 0x6d, 0x5f, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x64, 0x6d, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x0a, 0x09,
+	0x61, 0x69, 0x5f, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x61, 0x69, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x69, 0x5f,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x61, 0x69, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x74, 0x6f, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x74, 0x6f, 0x70,
+	0x12, 0x20, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x63, 0x72, 0x6f, 0x6c, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x63, 0x72, 0x6f,
+	0x6c, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x62, 0x6f, 0x74, 0x74, 0x6f,
+	0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x62, 0x6f,
+	0x74, 0x74, 0x6f, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63, 0x6f, 0x6c,
+	0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x63,
+	0x6f, 0x6c, 0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x70, 0x65,
+	0x63, 0x69, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x70, 0x65, 0x63, 0x69, 0x61, 0x6c, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x72, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x68, 0x61, 0x64, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x70, 0x72, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x68, 0x61, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x64, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x64, 0x6d, 0x61,
+	0x73, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x07, 0x6f, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x6d, 0x61, 0x72, 0x65, 0x61, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x64, 0x6d,
+	0x61, 0x72, 0x65, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x70, 0x65, 0x65, 0x64, 0x70, 0x6c, 0x75,
+	0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x73, 0x70, 0x65, 0x65, 0x64, 0x70, 0x6c,
+	0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x6f, 0x6e, 0x74, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x66, 0x6f, 0x6e, 0x74, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x73, 0x79, 0x6e, 0x63, 0x12, 0x1c,
+	0x0a, 0x09, 0x73, 0x70, 0x65, 0x65, 0x64, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x73, 0x70, 0x65, 0x65, 0x64, 0x73, 0x79, 0x6e, 0x63, 0x12, 0x1e, 0x0a, 0x0a,
+	0x66, 0x6f, 0x6e, 0x74, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x66, 0x6f, 0x6e, 0x74, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x12, 0x0a, 0x04,
+	0x62, 0x6f, 0x6c, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x62, 0x6f, 0x6c, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x66, 0x6f, 0x6e, 0x74, 0x62, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x13,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x66, 0x6f, 0x6e, 0x74, 0x62, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x12, 0x1b, 0x0a, 0x09, 0x64, 0x72, 0x61, 0x77, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x14, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x72, 0x61, 0x77, 0x54, 0x79, 0x70, 0x65, 0x42, 0x05, 0x5a,
+	0x03, 0x2f, 0x64, 0x6d, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -407,22 +1110,31 @@ func file_dm_proto_rawDescGZIP() []byte {
 	return file_dm_proto_rawDescData
 }
 
-var file_dm_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_dm_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_dm_proto_goTypes = []interface{}{
-	(*DmSegMobileReply)(nil), // 0: danmaku.DmSegMobileReply
-	(*DanmakuAIFlag)(nil),    // 1: danmaku.DanmakuAIFlag
-	(*DanmakuFlag)(nil),      // 2: danmaku.DanmakuFlag
-	(*DanmakuElem)(nil),      // 3: danmaku.DanmakuElem
+	(*DmSegMobileReply)(nil),     // 0: danmaku.DmSegMobileReply
+	(*DanmakuAIFlag)(nil),        // 1: danmaku.DanmakuAIFlag
+	(*DanmakuFlag)(nil),          // 2: danmaku.DanmakuFlag
+	(*DanmakuElem)(nil),          // 3: danmaku.DanmakuElem
+	(*DmWebViewReply)(nil),       // 4: danmaku.DmWebViewReply
+	(*DmSegConfig)(nil),          // 5: danmaku.DmSegConfig
+	(*DanmakuFlagConfig)(nil),    // 6: danmaku.DanmakuFlagConfig
+	(*CommandDm)(nil),            // 7: danmaku.CommandDm
+	(*DanmuWebPlayerConfig)(nil), // 8: danmaku.DanmuWebPlayerConfig
 }
 var file_dm_proto_depIdxs = []int32{
 	3, // 0: danmaku.DmSegMobileReply.elems:type_name -> danmaku.DanmakuElem
 	1, // 1: danmaku.DmSegMobileReply.ai_flag:type_name -> danmaku.DanmakuAIFlag
 	2, // 2: danmaku.DanmakuAIFlag.dm_flags:type_name -> danmaku.DanmakuFlag
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 3: danmaku.DmWebViewReply.dm_sge:type_name -> danmaku.DmSegConfig
+	6, // 4: danmaku.DmWebViewReply.flag:type_name -> danmaku.DanmakuFlagConfig
+	7, // 5: danmaku.DmWebViewReply.commandDms:type_name -> danmaku.CommandDm
+	8, // 6: danmaku.DmWebViewReply.dm_setting:type_name -> danmaku.DanmuWebPlayerConfig
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_dm_proto_init() }
@@ -479,6 +1191,66 @@ func file_dm_proto_init() {
 				return nil
 			}
 		}
+		file_dm_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DmWebViewReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dm_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DmSegConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dm_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DanmakuFlagConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dm_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandDm); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dm_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DanmuWebPlayerConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -486,7 +1258,7 @@ func file_dm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_dm_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
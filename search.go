@@ -0,0 +1,110 @@
+package biligo
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// 分类搜索的类型 即 SearchByType 的tp参数
+const (
+	SearchTypeVideo    = "video"         // 视频
+	SearchTypeBangumi  = "media_bangumi" // 番剧
+	SearchTypeFT       = "media_ft"      // 影视
+	SearchTypeLive     = "live"          // 直播间及主播
+	SearchTypeLiveRoom = "live_room"     // 直播间
+	SearchTypeLiveUser = "live_user"     // 主播
+	SearchTypeUser     = "bili_user"     // 用户
+	SearchTypeArticle  = "article"       // 专栏
+	SearchTypeTopic    = "topic"         // 话题
+)
+
+var searchHighlight = regexp.MustCompile(`<em class="[^"]*">(.*?)</em>`)
+
+// ParseSearchHighlight 去除搜索结果中的高亮标签
+//
+// 如 `<em class="keyword">原神</em>启动` 返回 `原神启动` 与命中的关键字 [原神]
+//
+// 同时会对html实体进行反转义
+func ParseSearchHighlight(s string) (string, []string) {
+	var keywords []string
+	for _, m := range searchHighlight.FindAllStringSubmatch(s, -1) {
+		keywords = append(keywords, html.UnescapeString(m[1]))
+	}
+	return html.UnescapeString(searchHighlight.ReplaceAllString(s, "$1")), keywords
+}
+
+// parseSearchResult 将某一类型的结果解析后追加到r中
+func parseSearchResult(r *SearchResult, tp string, data []byte) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	switch tp {
+	case SearchTypeVideo:
+		var v []*SearchResultVideo
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		for _, i := range v {
+			i.Title, i.Keywords = ParseSearchHighlight(i.Title)
+		}
+		r.Video = append(r.Video, v...)
+	case SearchTypeBangumi, SearchTypeFT:
+		var v []*SearchResultMedia
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		for _, i := range v {
+			i.Title, i.Keywords = ParseSearchHighlight(i.Title)
+			i.OrgTitle, _ = ParseSearchHighlight(i.OrgTitle)
+		}
+		r.Media = append(r.Media, v...)
+	case SearchTypeLiveRoom:
+		var v []*SearchResultLiveRoom
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		for _, i := range v {
+			i.Title, i.Keywords = ParseSearchHighlight(i.Title)
+		}
+		r.LiveRoom = append(r.LiveRoom, v...)
+	case SearchTypeLiveUser:
+		var v []*SearchResultLiveUser
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		for _, i := range v {
+			i.Uname, i.Keywords = ParseSearchHighlight(i.Uname)
+		}
+		r.LiveUser = append(r.LiveUser, v...)
+	case SearchTypeUser:
+		var v []*SearchResultUser
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		r.User = append(r.User, v...)
+	case SearchTypeArticle:
+		var v []*SearchResultArticle
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		for _, i := range v {
+			i.Title, i.Keywords = ParseSearchHighlight(i.Title)
+			i.Desc = strings.TrimSpace(i.Desc)
+		}
+		r.Article = append(r.Article, v...)
+	case SearchTypeTopic:
+		var v []*SearchResultTopic
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		for _, i := range v {
+			i.Title, i.Keywords = ParseSearchHighlight(i.Title)
+		}
+		r.Topic = append(r.Topic, v...)
+	default:
+		// 其他类型(如相簿、活动)暂不解析
+	}
+	return nil
+}
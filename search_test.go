@@ -0,0 +1,34 @@
+package biligo
+
+import "testing"
+
+func TestParseSearchHighlight(t *testing.T) {
+	s, k := ParseSearchHighlight(`<em class="keyword">原神</em>启动 &amp; <em class="keyword">Genshin</em>`)
+	if s != "原神启动 & Genshin" || len(k) != 2 || k[0] != "原神" || k[1] != "Genshin" {
+		t.Errorf("text: %s,keywords: %v", s, k)
+		t.FailNow()
+	}
+	if s, k = ParseSearchHighlight("无高亮"); s != "无高亮" || k != nil {
+		t.Errorf("text: %s,keywords: %v", s, k)
+		t.FailNow()
+	}
+}
+func TestParseSearchResult(t *testing.T) {
+	r := &SearchResult{ResultType: SearchTypeLive}
+	if err := parseSearchResult(r, SearchTypeLiveRoom, []byte(`[{"type":"live_room","roomid":1,"title":"<em class=\"keyword\">测试</em>直播"}]`)); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := parseSearchResult(r, SearchTypeVideo, []byte(`null`)); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if err := parseSearchResult(r, "photo", []byte(`[{}]`)); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(r.LiveRoom) != 1 || r.LiveRoom[0].Title != "测试直播" || r.LiveRoom[0].Keywords[0] != "测试" || r.Video != nil {
+		t.Errorf("%+v", r)
+		t.FailNow()
+	}
+}
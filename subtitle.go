@@ -0,0 +1,391 @@
+package biligo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 字幕状态 即 SubtitleInfo.Status
+const (
+	SubtitleStatusDraft     = 1 // 草稿
+	SubtitleStatusAuditing  = 3 // 待审核
+	SubtitleStatusRejected  = 4 // 审核驳回
+	SubtitleStatusPublished = 5 // 已发布
+)
+
+// NewSubtitle 使用网页端的默认样式创建BCC字幕
+func NewSubtitle(cues []*SubtitleCue) *Subtitle {
+	return &Subtitle{
+		FontSize:        0.4,
+		FontColor:       "#FFFFFF",
+		BackgroundAlpha: 0.5,
+		BackgroundColor: "#9C27B0",
+		Stroke:          "none",
+		Body:            cues,
+	}
+}
+
+// ParseSubtitleBCC 解析BCC格式字幕
+func ParseSubtitleBCC(data []byte) (*Subtitle, error) {
+	var s = &Subtitle{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// BCC 编码为BCC格式，可直接用于上传字幕
+func (s *Subtitle) BCC() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// SRT 转换为SRT格式
+//
+// 非底部居中的字幕使用 {\anN} 标记位置
+func (s *Subtitle) SRT() []byte {
+	var b bytes.Buffer
+	for i, c := range s.Body {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n", i+1, subtitleTime(c.From, ","), subtitleTime(c.To, ","))
+		if c.Location != 0 && c.Location != 2 {
+			fmt.Fprintf(&b, "{\\an%d}", c.Location)
+		}
+		b.WriteString(c.Content)
+		b.WriteString("\n\n")
+	}
+	return b.Bytes()
+}
+
+// VTT 转换为WebVTT格式
+//
+// 位置转换为 line 与 align 设置
+func (s *Subtitle) VTT() []byte {
+	var b bytes.Buffer
+	b.WriteString("WEBVTT\n\n")
+	for _, c := range s.Body {
+		fmt.Fprintf(&b, "%s --> %s%s\n", subtitleTime(c.From, "."), subtitleTime(c.To, "."), vttSettings(c.Location))
+		b.WriteString(vttEscape.Replace(c.Content))
+		b.WriteString("\n\n")
+	}
+	return b.Bytes()
+}
+
+// ASS 转换为ASS格式
+//
+// width height: 视频分辨率 传入0使用1920x1080
+//
+// 字体颜色、背景颜色与不透明度沿用BCC中的设置
+func (s *Subtitle) ASS(width, height int) []byte {
+	if width <= 0 || height <= 0 {
+		width, height = 1920, 1080
+	}
+	size := s.FontSize
+	if size <= 0 {
+		size = 0.4
+	}
+	fg, _ := parseHexColor(s.FontColor, 0xFFFFFF)
+	bg, _ := parseHexColor(s.BackgroundColor, 0x9C27B0)
+	bgAlpha := uint8(math.Round(255 * (1 - s.BackgroundAlpha)))
+
+	var b bytes.Buffer
+	writeASSHeader(&b, width, height, []string{
+		// BorderStyle为3时使用不透明背景框
+		fmt.Sprintf("Style: Default,Microsoft YaHei,%d,%s,%s,%s,%s,0,0,0,0,100,100,0,0,3,%d,0,2,20,20,%d,1",
+			int(math.Round(float64(height)*size/8)),
+			assColor(fg, 0), assColor(fg, 0), assColor(bg, bgAlpha), assColor(bg, bgAlpha),
+			height/270, height/20),
+	})
+	for _, c := range s.Body {
+		text := assEscape(c.Content)
+		if c.Location != 0 && c.Location != 2 {
+			text = fmt.Sprintf("{\\an%d}", c.Location) + text
+		}
+		fmt.Fprintf(&b, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", assTime(c.From), assTime(c.To), text)
+	}
+	return b.Bytes()
+}
+
+// ParseSubtitleSRT 解析SRT格式字幕
+//
+// 支持 {\anN} 位置标记
+func ParseSubtitleSRT(data []byte) (*Subtitle, error) {
+	cues, err := parseSubtitleBlocks(data, func(c *SubtitleCue, settings string) {
+		if m := assAlignTag.FindStringSubmatch(c.Content); m != nil {
+			c.Location, _ = strconv.Atoi(m[1])
+		}
+		c.Content = srtTag.ReplaceAllString(c.Content, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewSubtitle(cues), nil
+}
+
+// ParseSubtitleVTT 解析WebVTT格式字幕
+//
+// 会去除样式标签，并根据 line 与 align 设置推断位置
+func ParseSubtitleVTT(data []byte) (*Subtitle, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !bytes.HasPrefix(data, []byte("WEBVTT")) {
+		return nil, errors.New("invalid webvtt header")
+	}
+	cues, err := parseSubtitleBlocks(data, func(c *SubtitleCue, settings string) {
+		c.Location = vttLocation(settings)
+		c.Content = html.UnescapeString(vttTag.ReplaceAllString(c.Content, ""))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewSubtitle(cues), nil
+}
+
+// ParseSubtitleASS 解析ASS格式字幕
+//
+// 只读取 [Events] 中的 Dialogue，会去除特效标签，保留 \an 位置
+func ParseSubtitleASS(data []byte) (*Subtitle, error) {
+	var (
+		cues    []*SubtitleCue
+		events  bool
+		fields  []string
+		iStart  = -1
+		iEnd    = -1
+		iText   = -1
+		content = strings.ReplaceAll(strings.TrimPrefix(string(data), "\xef\xbb\xbf"), "\r\n", "\n")
+	)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			events = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !events {
+			continue
+		}
+		if strings.HasPrefix(line, "Format:") {
+			fields = strings.Split(strings.TrimPrefix(line, "Format:"), ",")
+			for i, f := range fields {
+				switch strings.TrimSpace(f) {
+				case "Start":
+					iStart = i
+				case "End":
+					iEnd = i
+				case "Text":
+					iText = i
+				}
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "Dialogue:") {
+			continue
+		}
+		if iStart < 0 || iEnd < 0 || iText != len(fields)-1 {
+			return nil, errors.New("invalid ass events format")
+		}
+		v := strings.SplitN(strings.TrimPrefix(line, "Dialogue:"), ",", len(fields))
+		if len(v) != len(fields) {
+			return nil, errors.Errorf("invalid dialogue: %s", line)
+		}
+		from, err := parseSubtitleTime(v[iStart])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseSubtitleTime(v[iEnd])
+		if err != nil {
+			return nil, err
+		}
+		c := &SubtitleCue{From: from, To: to, SID: len(cues) + 1, Location: 2}
+		text := v[iText]
+		if m := assAlignTag.FindStringSubmatch(text); m != nil {
+			c.Location, _ = strconv.Atoi(m[1])
+		}
+		c.Content = assUnescape.Replace(assOverride.ReplaceAllString(assBrace.Replace(text), ""))
+		cues = append(cues, c)
+	}
+	return NewSubtitle(cues), nil
+}
+
+var (
+	assAlignTag = regexp.MustCompile(`\{[^}]*\\an([1-9])[^}]*}`)
+	assOverride = regexp.MustCompile(`\{[^}]*}`)
+	// 转义的花括号先替换为占位符，避免被当作特效标签去除
+	assBrace    = strings.NewReplacer(`\{`, "\x00", `\}`, "\x01")
+	assUnescape = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ", "\x00", "{", "\x01", "}")
+	srtTag      = regexp.MustCompile(`\{\\[^}]*}`)
+	vttTag      = regexp.MustCompile(`</?[^>]+>`)
+	vttEscape   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// parseSubtitleBlocks 解析SRT与WebVTT共有的 时间行+文本 块结构
+//
+// after 用于处理各格式特有的位置与标签，settings为时间行中结束时间之后的内容
+func parseSubtitleBlocks(data []byte, after func(c *SubtitleCue, settings string)) ([]*SubtitleCue, error) {
+	content := strings.ReplaceAll(strings.TrimPrefix(string(data), "\xef\xbb\xbf"), "\r\n", "\n")
+
+	var cues []*SubtitleCue
+	for _, block := range strings.Split(content, "\n\n") {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		// 时间行之前可能有序号或标识，没有时间行的块(如WEBVTT头 NOTE STYLE)直接跳过
+		t := -1
+		for i, l := range lines {
+			if strings.Contains(l, "-->") {
+				t = i
+				break
+			}
+		}
+		if t < 0 {
+			continue
+		}
+		arrow := strings.SplitN(lines[t], "-->", 2)
+		end := strings.Fields(arrow[1])
+		if len(end) == 0 {
+			return nil, errors.Errorf("invalid timing: %s", lines[t])
+		}
+		from, err := parseSubtitleTime(arrow[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseSubtitleTime(end[0])
+		if err != nil {
+			return nil, err
+		}
+		c := &SubtitleCue{
+			From:     from,
+			To:       to,
+			SID:      len(cues) + 1,
+			Location: 2,
+			Content:  strings.Join(lines[t+1:], "\n"),
+		}
+		after(c, strings.Join(end[1:], " "))
+		cues = append(cues, c)
+	}
+	return cues, nil
+}
+
+// parseSubtitleTime 解析 hh:mm:ss,ms hh:mm:ss.ms mm:ss.ms h:mm:ss.cc 格式的时间
+func parseSubtitleTime(s string) (float64, error) {
+	parts := strings.Split(strings.Replace(strings.TrimSpace(s), ",", ".", 1), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, errors.Errorf("invalid time: %s", s)
+	}
+	var sec float64
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 || i < len(parts)-1 && strings.Contains(p, ".") {
+			return 0, errors.Errorf("invalid time: %s", s)
+		}
+		sec = sec*60 + v
+	}
+	return sec, nil
+}
+
+// subtitleTime 格式化为 hh:mm:ss{sep}mmm
+func subtitleTime(sec float64, sep string) string {
+	ms := int64(math.Round(sec * 1000))
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// vttSettings 位置转为WebVTT的cue设置
+func vttSettings(location int) string {
+	if location < 1 || location > 9 {
+		return ""
+	}
+	var s string
+	switch (location - 1) / 3 {
+	case 1:
+		s += " line:50%"
+	case 2:
+		s += " line:0"
+	}
+	switch (location - 1) % 3 {
+	case 0:
+		s += " align:left"
+	case 2:
+		s += " align:right"
+	}
+	return s
+}
+
+// vttLocation 由WebVTT的cue设置推断位置
+func vttLocation(settings string) int {
+	row, col := 0, 1
+	for _, f := range strings.Fields(settings) {
+		kv := strings.SplitN(f, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "line":
+			v := strings.SplitN(kv[1], ",", 2)[0]
+			if strings.HasSuffix(v, "%") {
+				p, _ := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+				switch {
+				case p < 33:
+					row = 2
+				case p < 67:
+					row = 1
+				}
+			} else if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				// 非负行号从顶部开始计算
+				row = 2
+			}
+		case "align":
+			switch kv[1] {
+			case "left", "start":
+				col = 0
+			case "right", "end":
+				col = 2
+			}
+		}
+	}
+	return row*3 + col + 1
+}
+
+// writeASSHeader 写入ASS的 [Script Info] [V4+ Styles] 与 [Events] 的Format行
+//
+// styles 为完整的 Style: 行
+func writeASSHeader(b *bytes.Buffer, width, height int, styles []string) {
+	fmt.Fprintf(b, "[Script Info]\n; Script generated by biligo\nScriptType: v4.00+\nPlayResX: %d\nPlayResY: %d\nWrapStyle: 2\nScaledBorderAndShadow: yes\n\n", width, height)
+	b.WriteString("[V4+ Styles]\nFormat: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	for _, s := range styles {
+		b.WriteString(s)
+		b.WriteByte('\n')
+	}
+	b.WriteString("\n[Events]\nFormat: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+}
+
+// assTime 格式化为 h:mm:ss.cc
+func assTime(sec float64) string {
+	cs := int64(math.Round(sec * 100))
+	if cs < 0 {
+		cs = 0
+	}
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// assColor RGB颜色转为ASS的 &HAABBGGRR 格式，alpha为透明度 0为不透明
+func assColor(rgb uint32, alpha uint8) string {
+	return fmt.Sprintf("&H%02X%02X%02X%02X", alpha, rgb&0xFF, rgb>>8&0xFF, rgb>>16&0xFF)
+}
+
+// assEscape 转义文本中的换行与特效标签
+func assEscape(s string) string {
+	return strings.NewReplacer("\r\n", `\N`, "\n", `\N`, "{", `\{`, "}", `\}`).Replace(s)
+}
+
+// parseHexColor 解析 #RRGGBB 格式的颜色，失败时返回def
+func parseHexColor(s string, def uint32) (uint32, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || v > 0xFFFFFF {
+		return def, errors.Errorf("invalid color: %s", s)
+	}
+	return uint32(v), nil
+}
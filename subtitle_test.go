@@ -0,0 +1,94 @@
+package biligo
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestSubtitle() *Subtitle {
+	return NewSubtitle([]*SubtitleCue{
+		{From: 0.1, To: 2.3, SID: 1, Location: 2, Content: "第一行\n第二行"},
+		{From: 3661.5, To: 3663.25, SID: 2, Location: 8, Content: "顶部 <b>&</b> {花括号}"},
+	})
+}
+func checkTestSubtitle(t *testing.T, s *Subtitle) {
+	want := newTestSubtitle()
+	if len(s.Body) != len(want.Body) {
+		t.Errorf("cues: %d", len(s.Body))
+		t.FailNow()
+	}
+	for i, c := range s.Body {
+		w := want.Body[i]
+		if c.From != w.From || c.To != w.To || c.Location != w.Location || c.Content != w.Content || c.SID != w.SID {
+			t.Errorf("want: %+v,got: %+v", w, c)
+			t.FailNow()
+		}
+	}
+}
+func TestSubtitle_SRT(t *testing.T) {
+	b := newTestSubtitle().SRT()
+	t.Log(string(b))
+	if !strings.Contains(string(b), "01:01:01,500 --> 01:01:03,250\n{\\an8}") {
+		t.FailNow()
+	}
+	s, err := ParseSubtitleSRT(b)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	checkTestSubtitle(t, s)
+}
+func TestSubtitle_VTT(t *testing.T) {
+	b := newTestSubtitle().VTT()
+	t.Log(string(b))
+	if !strings.Contains(string(b), "01:01:01.500 --> 01:01:03.250 line:0\n顶部 &lt;b&gt;&amp;&lt;/b&gt;") {
+		t.FailNow()
+	}
+	s, err := ParseSubtitleVTT(b)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	checkTestSubtitle(t, s)
+}
+func TestSubtitle_ASS(t *testing.T) {
+	b := newTestSubtitle().ASS(0, 0)
+	t.Log(string(b))
+	if !strings.Contains(string(b), "Dialogue: 0,1:01:01.50,1:01:03.25,Default,,0,0,0,,{\\an8}") {
+		t.FailNow()
+	}
+	s, err := ParseSubtitleASS(b)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	checkTestSubtitle(t, s)
+}
+func TestParseSubtitleVTT(t *testing.T) {
+	s, err := ParseSubtitleVTT([]byte("WEBVTT\r\n\r\nNOTE 注释\r\n\r\nid1\r\n00:01.000 --> 00:02.500 line:90% align:start\r\n<v 说话人>你好</v>\r\n"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(s.Body) != 1 || s.Body[0].From != 1 || s.Body[0].To != 2.5 || s.Body[0].Location != 1 || s.Body[0].Content != "你好" {
+		t.Errorf("%+v", s.Body[0])
+		t.FailNow()
+	}
+	if _, err = ParseSubtitleVTT([]byte("00:01.000 --> 00:02.500")); err == nil {
+		t.Error("want error when header is missing")
+		t.FailNow()
+	}
+}
+func TestSubtitle_BCC(t *testing.T) {
+	b, err := newTestSubtitle().BCC()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	s, err := ParseSubtitleBCC(b)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	checkTestSubtitle(t, s)
+}
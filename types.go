@@ -10,6 +10,7 @@ type Response struct {
 	Message string          `json:"message,omitempty"`
 	TTL     int             `json:"ttl,omitempty"`
 	Data    json.RawMessage `json:"data,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"` // 番剧等PGC接口的数据在result中而不是data
 }
 type Account struct {
 	MID      int64  `json:"mid"`       // 我的mid
@@ -162,6 +163,7 @@ type AccountSafetyOther struct {
 	SkipVerify bool `json:"skipVerify"` // 恒为false 作用尚不明确
 }
 type DanmakuResp struct {
+	State   int        `json:"state"` // 是否已关闭弹幕 0：未关闭 1：已关闭
 	Danmaku []*Danmaku `json:"danmaku"`
 }
 type Danmaku struct {
@@ -193,6 +195,87 @@ type Danmaku struct {
 	Pool     int    `json:"pool"`      // 弹幕池 0：普通池 1：字幕池 2：特殊池（代码/BAS弹幕）
 	IDStr    string `json:"id_str"`    // 弹幕dmid的字符串形式
 	Attr     int    `json:"attr"`      // 弹幕属性位(bin求AND) bit0:保护 bit1:直播 bit2:高赞
+	AIScore  uint32 `json:"ai_score"`  // 智能云屏蔽评分 来自 DmSegMobileReply.AiFlag 未评分时为0
+}
+
+// DanmakuView 弹幕元数据
+type DanmakuView struct {
+	State        int                 `json:"state"`         // 是否已关闭弹幕 0：未关闭 1：已关闭
+	Text         string              `json:"text"`          // 作用尚不明确
+	TextSide     string              `json:"text_side"`     // 作用尚不明确
+	SegPageSize  int64               `json:"seg_page_size"` // 实时弹幕分段时长 单位ms
+	SegTotal     int64               `json:"seg_total"`     // 实时弹幕分段数
+	Flag         *DanmakuViewFlag    `json:"flag"`          // 云屏蔽配置
+	SpecialDms   []string            `json:"special_dms"`   // 高级弹幕(BAS)专包url
+	CheckBox     bool                `json:"check_box"`     // 是否展示check box
+	Count        int64               `json:"count"`         // 弹幕总数
+	CommandDms   []*DanmakuCommand   `json:"command_dms"`   // 互动弹幕
+	Setting      *DanmakuViewSetting `json:"setting"`       // 当前用户的弹幕设置 未登录时为默认设置
+	ReportFilter []string            `json:"report_filter"` // 用户举报后屏蔽的弹幕
+}
+type DanmakuViewFlag struct {
+	RecFlag   int    `json:"rec_flag"`   // 云屏蔽等级
+	RecText   string `json:"rec_text"`   // 云屏蔽文案
+	RecSwitch int    `json:"rec_switch"` // 云屏蔽开关
+}
+
+// DanmakuCommand 互动弹幕
+type DanmakuCommand struct {
+	ID       int64  `json:"id"`       // 弹幕id
+	IDStr    string `json:"id_str"`   // 弹幕id的字符串形式
+	Oid      int64  `json:"oid"`      // 视频cid
+	MID      int64  `json:"mid"`      // 发送者mid
+	Progress int64  `json:"progress"` // 出现时间 单位ms
+	Ctime    string `json:"ctime"`    // 创建时间
+	Mtime    string `json:"mtime"`    // 发布时间
+	Content  string `json:"content"`  // 弹幕正文
+	// 互动弹幕指令
+	//
+	// #UP#：UP主头像弹幕
+	//
+	// #VOTE#：投票弹幕
+	//
+	// #LINK#：关联视频弹幕
+	//
+	// #ATTENTION#：关注弹幕
+	//
+	// #GRADE#：评分弹幕
+	//
+	// #RESERVE#：预约弹幕
+	Command string `json:"command"`
+	// 扩展信息 json格式，内容随指令不同 如UP主头像url、投票选项、关联视频的aid与标题
+	Extra string `json:"extra"`
+}
+type DanmakuViewSetting struct {
+	DmSwitch     bool    `json:"dm_switch"`    // 弹幕开关
+	AISwitch     bool    `json:"ai_switch"`    // 智能云屏蔽开关
+	AILevel      int     `json:"ai_level"`     // 智能云屏蔽等级
+	BlockTop     bool    `json:"blocktop"`     // 屏蔽顶部弹幕
+	BlockScroll  bool    `json:"blockscroll"`  // 屏蔽滚动弹幕
+	BlockBottom  bool    `json:"blockbottom"`  // 屏蔽底部弹幕
+	BlockColor   bool    `json:"blockcolor"`   // 屏蔽彩色弹幕
+	BlockSpecial bool    `json:"blockspecial"` // 屏蔽特殊弹幕
+	PreventShade bool    `json:"preventshade"` // 防挡字幕
+	DMask        bool    `json:"dmask"`        // 智能防挡弹幕(人像蒙版)
+	Opacity      float32 `json:"opacity"`      // 弹幕不透明度
+	DmArea       int     `json:"dmarea"`       // 弹幕显示区域
+	SpeedPlus    float32 `json:"speedplus"`    // 弹幕速度
+	FontSize     float32 `json:"fontsize"`     // 字体大小
+	ScreenSync   bool    `json:"screensync"`   // 跟随屏幕缩放比例
+	SpeedSync    bool    `json:"speedsync"`    // 根据播放倍速调整速度
+	FontFamily   string  `json:"fontfamily"`   // 字体类型
+	Bold         bool    `json:"bold"`         // 粗体
+	FontBorder   int     `json:"fontborder"`   // 描边类型
+	DrawType     string  `json:"draw_type"`    // 渲染类型
+}
+
+// DanmakuMask 智能防挡弹幕的人像蒙版信息
+type DanmakuMask struct {
+	CID     int64  `json:"cid"`      // 视频cid
+	Plat    int    `json:"plat"`     // 0：web端 1：客户端
+	FPS     int    `json:"fps"`      // 蒙版帧率
+	Time    int64  `json:"time"`     // 作用尚不明确
+	MaskURL string `json:"mask_url"` // 蒙版文件url
 }
 type SpaceVideoSearchResult struct {
 	List           *SpaceVideoSearchList           `json:"list"`            // 列表信息
@@ -712,6 +795,16 @@ type VideoPlayURLDash struct {
 	MinBufferTime float64                  `json:"min_buffer_time"` // 1.5 作用尚不明确
 	Video         []*VideoPlayURLDashMedia `json:"video"`           // 视频流信息
 	Audio         []*VideoPlayURLDashMedia `json:"audio"`           // 音频流信息
+	Dolby         *VideoPlayURLDashDolby   `json:"dolby"`           // 杜比全景声音频流信息 fnval需要包含256
+	Flac          *VideoPlayURLDashFlac    `json:"flac"`            // Hi-Res无损音频流信息
+}
+type VideoPlayURLDashDolby struct {
+	Type  int                      `json:"type"`  // 杜比音效类型 1：普通杜比音效 2：全景杜比音效
+	Audio []*VideoPlayURLDashMedia `json:"audio"` // 杜比音频流 无则为null
+}
+type VideoPlayURLDashFlac struct {
+	Display bool                   `json:"display"` // 是否在播放器显示无损音质按钮
+	Audio   *VideoPlayURLDashMedia `json:"audio"`   // 无损音频流 无则为null
 }
 type VideoPlayURLDashMedia struct {
 	ID           int                       `json:"id"`             // 音视频清晰度代码
@@ -753,21 +846,81 @@ type VideoShot struct {
 	Index    []int    `json:"index"`      // json数组格式截取时间表 单位为秒
 }
 type SearchAll struct {
-	SEID           string
-	Page           int
-	PageSize       int
-	NumResults     int
-	NumPages       int
-	SuggestKeyword string
-	RqtType        string
-	CostTime       *SearchCostTime
+	SEID           string          `json:"seid"`             // 搜索id
+	Page           int             `json:"page"`             // 页码
+	PageSize       int             `json:"pagesize"`         // 每页项数
+	NumResults     int             `json:"numResults"`       // 总计结果数
+	NumPages       int             `json:"numPages"`         // 总计页数
+	SuggestKeyword string          `json:"suggest_keyword"`  // 空 作用尚不明确
+	RqtType        string          `json:"rqt_type"`         // search
+	CostTime       *SearchCostTime `json:"cost_time"`        // 详细搜索用时
+	EggHit         int             `json:"egg_hit"`          // 是否命中彩蛋
+	PageInfo       *SearchPage     `json:"pageinfo"`         // 各类型结果数量
+	TopTlist       *SearchTopTlist `json:"top_tlist"`        // 各类型结果数量
+	ShowColumn     int             `json:"show_column"`      // 0
+	ShowModuleList []string        `json:"show_module_list"` // 返回结果的类型列表
+	Result         *SearchResult   `json:"-"`                // 各类型结果
 	// ExpList 作用尚不明确
-	EggHit         int
-	PageInfo       *SearchPage
-	TopTlist       *SearchTopTlist
-	ShowColumn     int
-	ShowModuleList []string
-	Result         *SearchResult
+}
+
+// SearchType 分类搜索结果
+type SearchType struct {
+	SEID           string          `json:"seid"`            // 搜索id
+	Page           int             `json:"page"`            // 页码
+	PageSize       int             `json:"pagesize"`        // 每页项数
+	NumResults     int             `json:"numResults"`      // 总计结果数 最大为1000
+	NumPages       int             `json:"numPages"`        // 总计页数 最大为50
+	SuggestKeyword string          `json:"suggest_keyword"` // 空 作用尚不明确
+	RqtType        string          `json:"rqt_type"`        // search
+	CostTime       *SearchCostTime `json:"cost_time"`       // 详细搜索用时
+	EggHit         int             `json:"egg_hit"`         // 是否命中彩蛋
+	ShowColumn     int             `json:"show_column"`     // 0
+	Result         *SearchResult   `json:"-"`               // 搜索结果
+}
+
+// SearchOption 分类搜索的筛选条件，不需要的留空
+type SearchOption struct {
+	// 排序方式
+	//
+	// 视频、专栏、相簿：totalrank 综合排序 click 最多点击 pubdate 最新发布 dm 最多弹幕 stow 最多收藏 scores 最多评论 attention 最多喜欢(专栏)
+	//
+	// 直播间：online 人气直播 live_time 最新开播
+	//
+	// 用户：0 默认排序 fans 粉丝数 level 用户等级
+	Order string
+	// 用户排序顺序 0：由高到低 1：由低到高
+	OrderSort int
+	// 视频时长筛选 0：全部时长 1：10分钟以下 2：10-30分钟 3：30-60分钟 4：60分钟以上
+	Duration int
+	// 视频分区筛选 0：全部分区
+	TID int
+	// 用户分类筛选 0：全部用户 1：UP主用户 2：普通用户 3：认证用户
+	UserType int
+	// 专栏及相簿分区筛选 0：全部分区
+	CategoryID int
+	// 页码 默认为1
+	Page int
+}
+
+// SearchSuggest 搜索建议
+type SearchSuggest struct {
+	Value string `json:"value"` // 建议的搜索词
+	Term  string `json:"term"`  // 建议的搜索词
+	Ref   int    `json:"ref"`   // 0
+	Name  string `json:"name"`  // 带高亮标签的搜索词 已去除高亮标签
+	SpID  int    `json:"spid"`  // 4
+}
+
+// SearchHotWord 热搜词
+type SearchHotWord struct {
+	Keyword  string `json:"keyword"`   // 热搜词
+	ShowName string `json:"show_name"` // 显示的文字
+	Icon     string `json:"icon"`      // 图标url 无则为空
+	Pos      int    `json:"pos"`       // 排名 从1开始
+	WordType int    `json:"word_type"` // 类型 4：新 5：热 ...
+	HotID    int64  `json:"hot_id"`    // 热搜id
+	URI      string `json:"uri"`       // 跳转链接 无则为空
+	Goto     string `json:"goto"`      // 跳转类型 无则为空
 }
 type SearchCostTime struct {
 	ParamsCheck         string `json:"params_check"`
@@ -834,10 +987,17 @@ type SearchTopTlist struct {
 }
 
 // SearchResult 在原搜索接口进行魔改，方便使用
+//
+// 结果中的 <em class="keyword"> 高亮标签均已去除，命中的关键字保存在各项的 Keywords 中
 type SearchResult struct {
-	ResultType string
-	Video      []*SearchResultVideo
-	Media      []*SearchResultMedia
+	ResultType string                  // 搜索类型 综合搜索为all 分类搜索为对应的 search_type
+	Video      []*SearchResultVideo    // 视频
+	Media      []*SearchResultMedia    // 番剧与影视 用 SearchResultMedia.Type 区分
+	LiveRoom   []*SearchResultLiveRoom // 直播间
+	LiveUser   []*SearchResultLiveUser // 主播
+	User       []*SearchResultUser     // 用户
+	Article    []*SearchResultArticle  // 专栏
+	Topic      []*SearchResultTopic    // 话题
 }
 type SearchResultVideo struct {
 	Type         string   `json:"type"`           // 结果类型 固定为video
@@ -849,7 +1009,7 @@ type SearchResultVideo struct {
 	ArcURL       string   `json:"arcurl"`         // 视频重定向URL
 	AID          int64    `json:"aid"`            // 稿件avid
 	BVID         string   `json:"bvid"`           // 稿件bvid
-	Title        string   `json:"title"`          // 视频标题 关键字用xml标签<em class="keyword">标注
+	Title        string   `json:"title"`          // 视频标题 已去除高亮标签
 	Description  string   `json:"description"`    // 视频简介
 	ArcRank      string   `json:"arcrank"`        // 恒为0 作用尚不明确
 	Pic          string   `json:"pic"`            // 视频封面url
@@ -867,6 +1027,7 @@ type SearchResultVideo struct {
 	IsPay        int      `json:"is_pay"`         // 空 作用尚不明确
 	IsUnionVideo int      `json:"is_union_video"` // 是否为合作视频 0:否 1:是
 	RankScore    int64    `json:"rank_score"`     // 结果排序量化值
+	Keywords     []string `json:"-"`              // 标题中命中的关键字
 	// RecTags      string NULL
 	// NewRecTags   []string 空数组
 }
@@ -874,8 +1035,8 @@ type SearchResultMedia struct {
 	Type           string                          `json:"type"`             // 结果类型 (media_bangumi:番剧 media_ft:影视)
 	MediaID        int64                           `json:"media_id"`         // 剧集mdid
 	SeasonID       int64                           `json:"season_id"`        // 剧集ssid
-	Title          string                          `json:"title"`            // 剧集标题 关键字用xml标签<em class="keyword">标注
-	OrgTitle       string                          `json:"org_title"`        // 剧集原名 关键字用xml标签<em class="keyword">标注 可为空
+	Title          string                          `json:"title"`            // 剧集标题 已去除高亮标签
+	OrgTitle       string                          `json:"org_title"`        // 剧集原名 已去除高亮标签 可为空
 	Cover          string                          `json:"cover"`            // 剧集封面url
 	MediaType      int                             `json:"media_type"`       // 剧集类型 (1:番剧 2:电影 3:纪录片 4:国创 5:电视剧 7:综艺)
 	Areas          string                          `json:"areas"`            // 地区
@@ -910,6 +1071,7 @@ type SearchResultMedia struct {
 	IsSelection    int                             `json:"is_selection"`     // 恒为1 作用尚不明确
 	Eps            []*SearchResultEp               `json:"eps"`              // 结果匹配的分集信息
 	Badges         []*SearchResultEpBadge          `json:"badges"`           // 剧集标志信息
+	Keywords       []string                        `json:"-"`                // 标题中命中的关键字
 }
 type SearchResultMediaScore struct {
 	UserCount int     `json:"user_count"` // 总计评分人数
@@ -945,6 +1107,105 @@ type SearchResultEpBadge struct {
 	BorderColorNight string `json:"border_color_night"` // 空
 	BgStyle          int    `json:"bg_style"`           // 恒为1
 }
+type SearchResultLiveRoom struct {
+	Type       string   `json:"type"`        // 结果类型 固定为live_room
+	RoomID     int64    `json:"roomid"`      // 直播间id
+	UID        int64    `json:"uid"`         // 主播mid
+	Uname      string   `json:"uname"`       // 主播昵称
+	Uface      string   `json:"uface"`       // 主播头像url
+	Title      string   `json:"title"`       // 直播间标题 已去除高亮标签
+	Cover      string   `json:"cover"`       // 关键帧截图url
+	UserCover  string   `json:"user_cover"`  // 直播间封面url
+	Online     int      `json:"online"`      // 在线人数
+	LiveStatus int      `json:"live_status"` // 直播状态 0：未开播 1：直播中
+	LiveTime   string   `json:"live_time"`   // 开播时间 YYYY-MM-DD hh:mm:ss
+	Area       int      `json:"area"`        // 子分区id
+	CateName   string   `json:"cate_name"`   // 子分区名
+	Tags       string   `json:"tags"`        // 直播间标签
+	Attentions int      `json:"attentions"`  // 主播粉丝数
+	RankScore  int64    `json:"rank_score"`  // 结果排序量化值
+	Keywords   []string `json:"-"`           // 标题中命中的关键字
+}
+type SearchResultLiveUser struct {
+	Type       string   `json:"type"`        // 结果类型 固定为live_user
+	UID        int64    `json:"uid"`         // 主播mid
+	Uname      string   `json:"uname"`       // 主播昵称 已去除高亮标签
+	Uface      string   `json:"uface"`       // 主播头像url
+	RoomID     int64    `json:"roomid"`      // 直播间id
+	IsLive     bool     `json:"is_live"`     // 是否正在直播
+	LiveStatus int      `json:"live_status"` // 直播状态 0：未开播 1：直播中
+	LiveTime   string   `json:"live_time"`   // 开播时间 未开播为 0000-00-00 00:00:00
+	Area       int      `json:"area"`        // 子分区id
+	CateName   string   `json:"cate_name"`   // 子分区名
+	Tags       string   `json:"tags"`        // 主播标签
+	Attentions int      `json:"attentions"`  // 主播粉丝数
+	RankScore  int64    `json:"rank_score"`  // 结果排序量化值
+	Keywords   []string `json:"-"`           // 昵称中命中的关键字
+}
+type SearchResultUser struct {
+	Type           string `json:"type"`      // 结果类型 固定为bili_user
+	MID            int64  `json:"mid"`       // 用户mid
+	Uname          string `json:"uname"`     // 用户昵称
+	Usign          string `json:"usign"`     // 用户签名
+	Fans           int    `json:"fans"`      // 粉丝数
+	Videos         int    `json:"videos"`    // 稿件数
+	Upic           string `json:"upic"`      // 用户头像url
+	Level          int    `json:"level"`     // 用户等级
+	Gender         int    `json:"gender"`    // 性别 1：男 2：女 3：保密
+	IsUpUser       int    `json:"is_upuser"` // 是否为UP主
+	IsLive         int    `json:"is_live"`   // 是否正在直播
+	RoomID         int64  `json:"room_id"`   // 直播间id
+	OfficialVerify *struct {
+		Type int    `json:"type"` // 认证类型 0：个人认证 1：机构认证 127：无
+		Desc string `json:"desc"` // 认证名称
+	} `json:"official_verify"` // 认证信息
+	Res []*struct {
+		AID          int64  `json:"aid"`            // 稿件avid
+		BVID         string `json:"bvid"`           // 稿件bvid
+		Title        string `json:"title"`          // 稿件标题
+		Pic          string `json:"pic"`            // 稿件封面url
+		Play         string `json:"play"`           // 播放数
+		Danmaku      int    `json:"dm"`             // 弹幕数
+		PubDate      int64  `json:"pubdate"`        // 投稿时间 时间戳
+		Duration     string `json:"duration"`       // 视频时长 格式: MM:SS
+		Description  string `json:"desc"`           // 视频简介
+		ArcURL       string `json:"arcurl"`         // 视频页面url
+		IsPay        int    `json:"is_pay"`         // 是否付费
+		IsUnionVideo int    `json:"is_union_video"` // 是否为合作视频
+	} `json:"res"` // 用户近期投稿
+}
+type SearchResultArticle struct {
+	Type         string   `json:"type"`          // 结果类型 固定为article
+	ID           int64    `json:"id"`            // 专栏cvid
+	MID          int64    `json:"mid"`           // UP主mid
+	Title        string   `json:"title"`         // 专栏标题 已去除高亮标签
+	Desc         string   `json:"desc"`          // 专栏摘要
+	ImageURLs    []string `json:"image_urls"`    // 封面url
+	View         int      `json:"view"`          // 阅读数
+	Like         int      `json:"like"`          // 点赞数
+	Reply        int      `json:"reply"`         // 评论数
+	PubTime      int64    `json:"pub_time"`      // 投稿时间 时间戳
+	CategoryID   int      `json:"category_id"`   // 分区id
+	CategoryName string   `json:"category_name"` // 分区名
+	TemplateID   int      `json:"template_id"`   // 模板id
+	RankScore    int64    `json:"rank_score"`    // 结果排序量化值
+	Keywords     []string `json:"-"`             // 标题中命中的关键字
+}
+type SearchResultTopic struct {
+	Type        string   `json:"type"`        // 结果类型 固定为topic
+	TpID        int64    `json:"tp_id"`       // 话题id
+	Title       string   `json:"title"`       // 话题标题 已去除高亮标签
+	Description string   `json:"description"` // 话题简介
+	Author      string   `json:"author"`      // 发起者昵称
+	MID         int64    `json:"mid"`         // 发起者mid
+	Cover       string   `json:"cover"`       // 封面url
+	ArcURL      string   `json:"arcurl"`      // 话题页面url
+	PubDate     int64    `json:"pubdate"`     // 发起时间 时间戳
+	Update      int64    `json:"update"`      // 更新时间 时间戳
+	Click       int      `json:"click"`       // 浏览数
+	Favourites  int      `json:"favourites"`  // 收藏数
+	Keywords    []string `json:"-"`           // 标题中命中的关键字
+}
 type DanmakuPostResult struct {
 	Action  string `json:"action"`   // 空 作用尚不明确
 	Dmid    uint64 `json:"dmid"`     // 弹幕dmid
@@ -1509,6 +1770,115 @@ type LiveAreaInfo struct {
 		CateID          string `json:"cate_id,omitempty"`
 	} `json:"list"`
 }
+
+// LiveArea 直播父分区
+type LiveArea struct {
+	ID       int            `json:"id"`
+	Name     string         `json:"name"`
+	Children []*LiveSubArea `json:"children"` // 子分区
+}
+
+// LiveSubArea 直播子分区
+type LiveSubArea struct {
+	ID              int    `json:"id"`
+	ParentID        int    `json:"parent_id"` // 父分区ID
+	ParentName      string `json:"parent_name"`
+	OldAreaID       int    `json:"old_area_id"`
+	Name            string `json:"name"`
+	Pic             string `json:"pic"`               // 分区图标url
+	HotStatus       int    `json:"hot_status"`        // 1:热门分区
+	LockStatus      int    `json:"lock_status"`       // 1:已锁定 无法选择该分区开播
+	ComplexAreaName string `json:"complex_area_name"` // 分区别名
+	AreaType        int    `json:"area_type"`
+}
+
+// LiveAreaRoomList 分区下的直播间列表
+type LiveAreaRoomList struct {
+	Count   int             `json:"count"`    // 正在直播的直播间总数
+	HasMore int             `json:"has_more"` // 0:没有下一页 1:有下一页
+	List    []*LiveAreaRoom `json:"list"`
+	NewTags []*struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name"`      // 如 全部 最新开播
+		SortType string `json:"sort_type"` // 作为 LiveGetAreaRoomList 的sort参数
+		Sort     int    `json:"sort"`
+	} `json:"new_tags"` // 该分区可用的排序方式
+}
+
+// LiveAreaRoom 分区下的直播间
+type LiveAreaRoom struct {
+	RoomID      int64  `json:"roomid"` // 真实直播间ID
+	UID         int64  `json:"uid"`
+	Title       string `json:"title"`
+	Uname       string `json:"uname"`
+	Face        string `json:"face"`
+	Online      int64  `json:"online"` // 人气值
+	Cover       string `json:"cover"`
+	UserCover   string `json:"user_cover"`   // 用户设置的封面
+	SystemCover string `json:"system_cover"` // 关键帧截图
+	Link        string `json:"link"`         // 直播间相对地址 如 /21452505
+	ParentID    int    `json:"parent_id"`    // 父分区ID
+	ParentName  string `json:"parent_name"`
+	AreaID      int    `json:"area_id"` // 子分区ID
+	AreaName    string `json:"area_name"`
+	SessionID   string `json:"session_id"`
+	GroupID     int    `json:"group_id"`
+	WatchedShow *struct {
+		Switch    bool   `json:"switch"`
+		Num       int64  `json:"num"`        // 看过的人数
+		TextSmall string `json:"text_small"` // 如 1.2万
+		TextLarge string `json:"text_large"` // 如 1.2万人看过
+	} `json:"watched_show"`
+}
+
+// LiveRecommendRoom 首页推荐的直播间
+type LiveRecommendRoom struct {
+	RoomID           int64  `json:"roomid"` // 真实直播间ID
+	UID              int64  `json:"uid"`
+	Title            string `json:"title"`
+	Uname            string `json:"uname"`
+	Face             string `json:"face"`
+	Online           int64  `json:"online"` // 人气值
+	Cover            string `json:"cover"`
+	Keyframe         string `json:"keyframe"` // 关键帧截图
+	Link             string `json:"link"`
+	AreaV2ID         int    `json:"area_v2_id"` // 子分区ID
+	AreaV2Name       string `json:"area_v2_name"`
+	AreaV2ParentID   int    `json:"area_v2_parent_id"` // 父分区ID
+	AreaV2ParentName string `json:"area_v2_parent_name"`
+	BroadcastType    int    `json:"broadcast_type"` // 0:普通直播 1:手机直播
+	IsAd             bool   `json:"is_ad"`          // 是否为广告
+	WatchedShow      *struct {
+		Switch    bool   `json:"switch"`
+		Num       int64  `json:"num"`        // 看过的人数
+		TextSmall string `json:"text_small"` // 如 1.2万
+		TextLarge string `json:"text_large"` // 如 1.2万人看过
+	} `json:"watched_show"`
+}
+
+// LiveFollowingList 关注的主播中正在直播的直播间
+type LiveFollowingList struct {
+	Count int                  `json:"count"` // 正在直播的总数
+	List  []*LiveFollowingRoom `json:"list"`
+}
+
+// LiveFollowingRoom 关注的主播正在直播的直播间
+type LiveFollowingRoom struct {
+	RoomID         int64  `json:"roomid"` // 真实直播间ID
+	UID            int64  `json:"uid"`
+	Uname          string `json:"uname"`
+	Face           string `json:"face"`
+	Title          string `json:"title"`
+	Cover          string `json:"cover"`
+	Keyframe       string `json:"keyframe"` // 关键帧截图
+	Link           string `json:"link"`
+	Online         int64  `json:"online"`    // 人气值
+	LiveTime       int64  `json:"live_time"` // 开播时间 时间戳
+	AreaV2ID       int    `json:"area_v2_id"`
+	AreaV2Name     string `json:"area_v2_name"`
+	ParentAreaID   int    `json:"parent_area_id"`
+	ParentAreaName string `json:"parent_area_name"`
+}
 type LiveGuardList struct {
 	Info *struct {
 		Num              int `json:"num"`  // 大航海总数
@@ -1575,6 +1945,321 @@ type LivePlayURL struct {
 	} `json:"durl"`
 	IsDashAuto bool `json:"is_dash_auto"`
 }
+
+// LivePlayInfo 直播间信息与直播流
+type LivePlayInfo struct {
+	RoomID      int64 `json:"room_id"`     // 真实直播间ID
+	ShortID     int   `json:"short_id"`    // 短号
+	UID         int64 `json:"uid"`         // 主播mid
+	LiveStatus  int   `json:"live_status"` // 0:未开播 1:直播中 2:轮播中
+	LiveTime    int64 `json:"live_time"`   // 开播时间 时间戳
+	PlayURLInfo *struct {
+		PlayURL *struct {
+			CID     int64 `json:"cid"` // 直播间ID
+			GQnDesc []*struct {
+				Qn   int    `json:"qn"`
+				Desc string `json:"desc"`
+			} `json:"g_qn_desc"` // 清晰度列表
+			Stream []*LiveStream `json:"stream"` // 直播流 按协议分组
+		} `json:"playurl"`
+	} `json:"playurl_info"` // 未开播时为nil
+}
+
+// LiveStream 一种协议的直播流
+type LiveStream struct {
+	ProtocolName string `json:"protocol_name"` // http_stream:FLV http_hls:HLS
+	Format       []*struct {
+		FormatName string             `json:"format_name"` // flv ts fmp4
+		Codec      []*LiveStreamCodec `json:"codec"`
+	} `json:"format"`
+}
+
+// LiveStreamCodec 一种编码的直播流
+type LiveStreamCodec struct {
+	CodecName string `json:"codec_name"` // avc hevc
+	CurrentQn int    `json:"current_qn"` // 当前清晰度
+	AcceptQn  []int  `json:"accept_qn"`  // 可选清晰度
+	BaseURL   string `json:"base_url"`   // 直播流路径
+	URLInfo   []*struct {
+		Host      string `json:"host"`       // 域名
+		Extra     string `json:"extra"`      // url参数
+		StreamTTL int    `json:"stream_ttl"` // 有效期 单位为秒
+	} `json:"url_info"` // 可用的CDN 完整url为 Host+BaseURL+Extra
+}
+
+// LiveRoomStatus 批量获取的直播间状态
+type LiveRoomStatus struct {
+	UID              int64  `json:"uid"`
+	Uname            string `json:"uname"`
+	Face             string `json:"face"`
+	RoomID           int64  `json:"room_id"`  // 真实直播间ID
+	ShortID          int    `json:"short_id"` // 短号
+	Title            string `json:"title"`
+	Tags             string `json:"tags"`        // 标签 逗号分隔
+	Online           int64  `json:"online"`      // 人气值
+	LiveStatus       int    `json:"live_status"` // 0:未开播 1:直播中 2:轮播中
+	LiveTime         int64  `json:"live_time"`   // 开播时间 时间戳 未开播时为0
+	AreaV2ID         int    `json:"area_v2_id"`  // 分区ID
+	AreaV2Name       string `json:"area_v2_name"`
+	AreaV2ParentID   int    `json:"area_v2_parent_id"` // 父分区ID
+	AreaV2ParentName string `json:"area_v2_parent_name"`
+	CoverFromUser    string `json:"cover_from_user"` // 封面
+	Keyframe         string `json:"keyframe"`        // 关键帧截图
+	BroadcastType    int    `json:"broadcast_type"`  // 0:普通直播 1:手机直播
+	TagName          string `json:"tag_name"`
+	HiddenTill       int64  `json:"hidden_till"`
+	LockTill         int64  `json:"lock_till"`
+}
+
+// LiveDanmakuHistory 直播间最近的弹幕
+type LiveDanmakuHistory struct {
+	Admin []*LiveHistoryDanmaku `json:"admin"` // 房管弹幕
+	Room  []*LiveHistoryDanmaku `json:"room"`  // 普通弹幕 按时间排序
+}
+
+// LiveHistoryDanmaku 直播间历史弹幕
+type LiveHistoryDanmaku struct {
+	Text       string        `json:"text"`
+	DmType     int           `json:"dm_type"` // 0:文字 1:表情
+	UID        int64         `json:"uid"`
+	Nickname   string        `json:"nickname"`
+	Timeline   string        `json:"timeline"` // 发送时间 如 2021-08-01 12:00:00
+	IsAdmin    int           `json:"isadmin"`
+	Vip        int           `json:"vip"`
+	Svip       int           `json:"svip"`
+	Medal      []interface{} `json:"medal"`      // 粉丝勋章 0:等级 1:勋章名 2:主播昵称 3:直播间ID 未佩戴时为空
+	UserLevel  []interface{} `json:"user_level"` // 0:用户直播等级
+	GuardLevel int           `json:"guard_level"`
+	IDStr      string        `json:"id_str"`
+	CheckInfo  *struct {
+		Ts int64  `json:"ts"` // 发送时间 时间戳
+		Ct string `json:"ct"`
+	} `json:"check_info"`
+	Emoticon *struct {
+		EmoticonUnique string `json:"emoticon_unique"`
+		URL            string `json:"url"`
+		Width          int    `json:"width"`
+		Height         int    `json:"height"`
+	} `json:"emoticon"` // 表情弹幕
+}
+
+// LiveOnlineGoldRank 高能榜
+type LiveOnlineGoldRank struct {
+	OnlineNum      int                       `json:"onlineNum"` // 榜上人数
+	OnlineRankItem []*LiveOnlineGoldRankItem `json:"OnlineRankItem"`
+}
+
+// LiveOnlineGoldRankItem 高能榜用户
+type LiveOnlineGoldRankItem struct {
+	UserRank   int    `json:"userRank"` // 排名
+	UID        int64  `json:"uid"`
+	Name       string `json:"name"`
+	Face       string `json:"face"`
+	Score      int64  `json:"score"` // 贡献值 即本场赠送的金瓜子/100
+	GuardLevel int    `json:"guard_level"`
+	MedalInfo  *struct {
+		GuardLevel       int    `json:"guardLevel"`
+		MedalColorStart  int64  `json:"medalColorStart"`
+		MedalColorEnd    int64  `json:"medalColorEnd"`
+		MedalColorBorder int64  `json:"medalColorBorder"`
+		MedalName        string `json:"medalName"`
+		Level            int    `json:"level"`
+		TargetID         int64  `json:"targetId"`
+		IsLight          int    `json:"isLight"`
+	} `json:"medalInfo"` // 佩戴的粉丝勋章 未佩戴时为nil
+}
+
+// LiveFansMembers 粉丝团成员
+type LiveFansMembers struct {
+	Num  int               `json:"num"` // 粉丝团总人数
+	Item []*LiveFansMember `json:"item"`
+}
+
+// LiveFansMember 粉丝团成员
+type LiveFansMember struct {
+	UID        int64  `json:"uid"`
+	Name       string `json:"name"`
+	Face       string `json:"face"`
+	Rank       int    `json:"rank"`
+	Score      int64  `json:"score"` // 亲密度
+	GuardLevel int    `json:"guard_level"`
+	MedalInfo  *struct {
+		MedalName  string `json:"medal_name"`
+		Level      int    `json:"level"`
+		GuardLevel int    `json:"guard_level"`
+		IsLighted  int    `json:"is_lighted"`
+	} `json:"medal_info"`
+}
+
+// LiveRecordList 往期直播记录
+type LiveRecordList struct {
+	Count int           `json:"count"` // 总数
+	List  []*LiveRecord `json:"list"`
+}
+
+// LiveRecord 一场直播的记录
+type LiveRecord struct {
+	RID            string `json:"rid"` // 记录ID
+	Title          string `json:"title"`
+	Cover          string `json:"cover"`
+	AreaID         int    `json:"area_id"`
+	AreaName       string `json:"area_name"`
+	ParentAreaID   int    `json:"parent_area_id"`
+	ParentAreaName string `json:"parent_area_name"`
+	StartTimestamp int64  `json:"start_timestamp"` // 开播时间 时间戳
+	EndTimestamp   int64  `json:"end_timestamp"`   // 下播时间 时间戳
+	Online         int64  `json:"online"`          // 人气峰值
+	DanmuNum       int64  `json:"danmu_num"`       // 弹幕数
+	Length         int64  `json:"length"`          // 时长 单位为毫秒
+}
+
+// LiveReplayList 直播回放列表
+type LiveReplayList struct {
+	ReplayInfo []*LiveReplay `json:"replay_info"`
+	Pagination *struct {
+		Page     int `json:"page"`
+		PageSize int `json:"page_size"`
+		Total    int `json:"total"`
+	} `json:"pagination"`
+}
+
+// LiveReplay 一场直播的回放
+type LiveReplay struct {
+	LiveInfo *struct {
+		LiveKey   string `json:"live_key"` // 本场直播的标识 与 LiveStartResult.LiveKey 相同
+		StartTime int64  `json:"start_time"`
+		EndTime   int64  `json:"end_time"`
+		Title     string `json:"title"`
+		Cover     string `json:"cover"`
+		Online    int64  `json:"online"`
+	} `json:"live_info"`
+	VideoInfo *struct {
+		ReplayStatus  int    `json:"replay_status"` // 0:生成中 1:已生成 2:生成失败
+		EstimatedTime int64  `json:"estimated_time"`
+		Duration      int64  `json:"duration"` // 时长 单位为秒
+		AlarmInfo     string `json:"alarm_info"`
+	} `json:"video_info"`
+}
+
+// LiveStartResult 开播结果
+type LiveStartResult struct {
+	Change int    `json:"change"` // 直播状态是否改变 0:未改变 1:已改变
+	Status string `json:"status"` // LIVE:直播中
+	RTMP   *struct {
+		Addr     string `json:"addr"`     // 推流服务器地址
+		Code     string `json:"code"`     // 推流码 即串流密钥
+		NewLink  string `json:"new_link"` // 获取CDN推流节点的地址
+		Provider string `json:"provider"` // 推流服务提供商
+	} `json:"rtmp"`
+	Protocols []*struct {
+		Protocol string `json:"protocol"` // rtmp
+		Addr     string `json:"addr"`
+		Code     string `json:"code"`
+		NewLink  string `json:"new_link"`
+		Provider string `json:"provider"`
+	} `json:"protocols"`
+	TryTime      string `json:"try_time"`
+	LiveKey      string `json:"live_key"`       // 本场直播的标识
+	NeedFaceAuth bool   `json:"need_face_auth"` // 需要人脸认证后才能开播
+}
+
+// LiveSendGiftResult 赠送礼物结果
+type LiveSendGiftResult struct {
+	UID        int64  `json:"uid"`
+	Uname      string `json:"uname"`
+	GuardLevel int    `json:"guard_level"`
+	RUID       int64  `json:"ruid"` // 主播mid
+	RoomID     int64  `json:"room_id"`
+	GiftID     int    `json:"gift_id"`
+	GiftName   string `json:"gift_name"`
+	GiftNum    int    `json:"gift_num"`
+	Price      int    `json:"price"`     // 单价
+	CoinType   string `json:"coin_type"` // gold silver
+	TID        string `json:"tid"`       // 订单号
+}
+
+// LiveBagGift 背包礼物
+type LiveBagGift struct {
+	BagID        int64  `json:"bag_id"`
+	GiftID       int    `json:"gift_id"`
+	GiftName     string `json:"gift_name"`
+	GiftNum      int    `json:"gift_num"`
+	GiftType     int    `json:"gift_type"`
+	BindRoomID   int64  `json:"bind_roomid"`    // 只能在该直播间使用 0为不限
+	BindRoomText string `json:"bind_room_text"` // 绑定直播间的说明
+	ExpireAt     int64  `json:"expire_at"`      // 过期时间 时间戳 0为永久
+	CornerMark   string `json:"corner_mark"`    // 剩余有效期 如 永久 3天
+	CardImage    string `json:"card_image"`
+}
+
+// LiveMyMedals 自己的粉丝勋章
+type LiveMyMedals struct {
+	Count int `json:"count"` // 勋章总数
+	Items []*struct {
+		MedalID       int64  `json:"medal_id"`
+		MedalName     string `json:"medal_name"`
+		Level         int    `json:"level"`
+		Intimacy      int    `json:"intimacy"`      // 当前亲密度
+		NextIntimacy  int    `json:"next_intimacy"` // 升级所需亲密度
+		TodayFeed     int    `json:"today_feed"`    // 今日获得的亲密度
+		DayLimit      int    `json:"day_limit"`     // 每日亲密度上限
+		TargetID      int64  `json:"target_id"`     // 主播mid
+		TargetName    string `json:"target_name"`   // 主播昵称
+		RoomID        int64  `json:"roomid"`
+		IsLighted     int    `json:"is_lighted"`     // 是否点亮
+		GuardLevel    int    `json:"guard_level"`    // 1:总督 2:提督 3:舰长
+		WearingStatus int    `json:"wearing_status"` // 是否正在佩戴
+		MedalColor    int64  `json:"medal_color"`
+	} `json:"items"`
+	PageInfo *struct {
+		TotalPage int `json:"total_page"`
+		CurPage   int `json:"cur_page"`
+	} `json:"page_info"`
+}
+
+// LiveSignResult 直播签到结果
+type LiveSignResult struct {
+	Text        string `json:"text"`        // 签到奖励
+	SpecialText string `json:"specialText"` // 额外奖励
+	AllDays     int    `json:"allDays"`     // 本月天数
+	HadSignDays int    `json:"hadSignDays"` // 本月已签到天数
+	IsBonusDay  int    `json:"isBonusDay"`  // 是否有额外奖励
+}
+
+// LiveAdminList 房管列表
+type LiveAdminList struct {
+	Page *struct {
+		Page       int `json:"page"`
+		PageSize   int `json:"page_size"`
+		TotalPage  int `json:"total_page"`
+		TotalCount int `json:"total_count"`
+	} `json:"page"`
+	Data []*struct {
+		UID   int64  `json:"uid"`
+		Uname string `json:"uname"`
+		Face  string `json:"face"`
+		Ctime string `json:"ctime"` // 任命时间 如 2021-08-01 12:00:00
+	} `json:"data"`
+	MaxRoomAnchorsNumber int `json:"max_room_anchors_number"` // 房管数量上限
+}
+
+// LiveMutedUsers 直播间禁言列表
+type LiveMutedUsers struct {
+	Data []*struct {
+		ID         int64  `json:"id"`
+		TUID       int64  `json:"tuid"`  // 被禁言用户mid
+		TName      string `json:"tname"` // 被禁言用户昵称
+		UID        int64  `json:"uid"`   // 操作者mid
+		Name       string `json:"name"`  // 操作者昵称
+		Ctime      string `json:"ctime"` // 禁言时间
+		IsAnchor   int    `json:"is_anchor"`
+		Face       string `json:"face"`
+		AdminLevel int    `json:"admin_level"`
+	} `json:"data"`
+	Total     int `json:"total"`
+	TotalPage int `json:"total_page"`
+}
+
 type LiveAllGiftInfo struct {
 	List []*struct {
 		ID                int    `json:"id"`
@@ -1657,6 +2342,169 @@ type LiveAllGiftInfo struct {
 		Name  string `json:"name"`
 	} `json:"guard_resources"`
 }
+
+// LiveMedal 直播消息中的粉丝勋章
+type LiveMedal struct {
+	Level      int    `json:"medal_level"`   // 勋章等级
+	Name       string `json:"medal_name"`    // 勋章名
+	AnchorName string `json:"anchor_uname"`  // 勋章所属主播昵称 部分消息中为空
+	RoomID     int64  `json:"anchor_roomid"` // 勋章所属直播间ID 部分消息中为0
+	TargetID   int64  `json:"target_id"`     // 勋章所属主播mid
+	Color      int64  `json:"medal_color"`   // 勋章颜色 十进制RGB888值
+	GuardLevel int    `json:"guard_level"`   // 0:无 1:总督 2:提督 3:舰长
+	IsLighted  int    `json:"is_lighted"`    // 勋章是否点亮 0:熄灭 1:点亮
+}
+
+// LiveDanmaku 直播弹幕 DANMU_MSG
+type LiveDanmaku struct {
+	UID        int64         `json:"uid"`         // 发送者mid 匿名连接时可能为0
+	Uname      string        `json:"uname"`       // 发送者昵称 匿名连接时可能被打码
+	Content    string        `json:"content"`     // 弹幕内容
+	Mode       int           `json:"mode"`        // 弹幕类型 1:滚动 4:底部 5:顶部
+	FontSize   int           `json:"font_size"`   // 弹幕字号
+	Color      int64         `json:"color"`       // 弹幕颜色 十进制RGB888值
+	Timestamp  int64         `json:"timestamp"`   // 发送时间 毫秒时间戳
+	IsAdmin    bool          `json:"is_admin"`    // 是否为房管
+	UserLevel  int           `json:"user_level"`  // 用户直播等级
+	GuardLevel int           `json:"guard_level"` // 0:无 1:总督 2:提督 3:舰长
+	Medal      *LiveMedal    `json:"medal"`       // 佩戴的粉丝勋章 未佩戴时为nil
+	Emoticon   *LiveEmoticon `json:"emoticon"`    // 表情弹幕 非表情弹幕时为nil
+}
+
+// LiveEmoticon 直播表情
+type LiveEmoticon struct {
+	Unique string `json:"emoticon_unique"` // 表情标识
+	URL    string `json:"url"`             // 表情图片url
+	Width  int    `json:"width"`           // 图片宽度
+	Height int    `json:"height"`          // 图片高度
+}
+
+// LiveGift 礼物 SEND_GIFT
+type LiveGift struct {
+	UID          int64      `json:"uid"`            // 赠送者mid
+	Uname        string     `json:"uname"`          // 赠送者昵称
+	Face         string     `json:"face"`           // 赠送者头像url
+	GiftID       int64      `json:"giftId"`         // 礼物ID
+	GiftName     string     `json:"giftName"`       // 礼物名
+	Num          int        `json:"num"`            // 数量
+	Price        int64      `json:"price"`          // 单价 金瓜子礼物单位为1/1000元
+	TotalCoin    int64      `json:"total_coin"`     // 总价
+	CoinType     string     `json:"coin_type"`      // gold:金瓜子 silver:银瓜子
+	Action       string     `json:"action"`         // 动作 如 投喂
+	Timestamp    int64      `json:"timestamp"`      // 赠送时间 时间戳
+	BatchComboID string     `json:"batch_combo_id"` // 连击ID 与 LiveComboGift 对应
+	GuardLevel   int        `json:"guard_level"`    // 0:无 1:总督 2:提督 3:舰长
+	Medal        *LiveMedal `json:"medal_info"`     // 佩戴的粉丝勋章
+}
+
+// LiveComboGift 礼物连击 COMBO_SEND
+type LiveComboGift struct {
+	UID            int64      `json:"uid"`              // 赠送者mid
+	Uname          string     `json:"uname"`            // 赠送者昵称
+	GiftID         int64      `json:"gift_id"`          // 礼物ID
+	GiftName       string     `json:"gift_name"`        // 礼物名
+	ComboNum       int        `json:"combo_num"`        // 当前连击数
+	TotalNum       int        `json:"total_num"`        // 礼物总数
+	ComboTotalCoin int64      `json:"combo_total_coin"` // 连击总价
+	BatchComboID   string     `json:"batch_combo_id"`   // 连击ID
+	Action         string     `json:"action"`           // 动作 如 投喂
+	RUID           int64      `json:"ruid"`             // 主播mid
+	RUname         string     `json:"r_uname"`          // 主播昵称
+	Medal          *LiveMedal `json:"medal_info"`       // 佩戴的粉丝勋章
+}
+
+// LiveSuperChat 醒目留言 SUPER_CHAT_MESSAGE
+type LiveSuperChat struct {
+	ID              int64  `json:"id"`               // 醒目留言ID
+	UID             int64  `json:"uid"`              // 发送者mid
+	Price           int    `json:"price"`            // 价格 单位为元
+	Message         string `json:"message"`          // 留言内容
+	MessageTrans    string `json:"message_trans"`    // 日文翻译 没有时为空
+	StartTime       int64  `json:"start_time"`       // 开始显示时间 时间戳
+	EndTime         int64  `json:"end_time"`         // 结束显示时间 时间戳
+	Time            int    `json:"time"`             // 显示时长 单位为秒
+	BackgroundColor string `json:"background_color"` // 背景颜色 #RRGGBB
+	UserInfo        *struct {
+		Uname      string `json:"uname"`       // 发送者昵称
+		Face       string `json:"face"`        // 发送者头像url
+		GuardLevel int    `json:"guard_level"` // 0:无 1:总督 2:提督 3:舰长
+		UserLevel  int    `json:"user_level"`  // 用户直播等级
+	} `json:"user_info"`
+	Medal *LiveMedal `json:"medal_info"` // 佩戴的粉丝勋章
+}
+
+// LiveGuardBuy 开通大航海 GUARD_BUY
+type LiveGuardBuy struct {
+	UID        int64  `json:"uid"`         // 开通者mid
+	Username   string `json:"username"`    // 开通者昵称
+	GuardLevel int    `json:"guard_level"` // 1:总督 2:提督 3:舰长
+	Num        int    `json:"num"`         // 数量 单位为月
+	Price      int64  `json:"price"`       // 价格 单位为金瓜子
+	GiftID     int64  `json:"gift_id"`     // 礼物ID
+	GiftName   string `json:"gift_name"`   // 礼物名
+	StartTime  int64  `json:"start_time"`  // 开通时间 时间戳
+	EndTime    int64  `json:"end_time"`    // 时间戳
+}
+
+// LiveInteract 进入直播间、关注、分享 INTERACT_WORD
+type LiveInteract struct {
+	UID   int64  `json:"uid"`   // 用户mid
+	Uname string `json:"uname"` // 用户昵称
+	// 互动类型
+	//
+	// 1：进入直播间
+	//
+	// 2：关注
+	//
+	// 3：分享
+	//
+	// 4：特别关注
+	//
+	// 5：互相关注
+	MsgType   int        `json:"msg_type"`
+	RoomID    int64      `json:"roomid"`     // 直播间ID
+	Timestamp int64      `json:"timestamp"`  // 时间戳
+	Medal     *LiveMedal `json:"fans_medal"` // 佩戴的粉丝勋章
+}
+
+// LiveOnlineRankCount 高能用户数 ONLINE_RANK_COUNT
+type LiveOnlineRankCount struct {
+	Count int `json:"count"` // 高能用户数
+}
+
+// LiveOnlineRank 高能榜 ONLINE_RANK_V2
+type LiveOnlineRank struct {
+	RankType string `json:"rank_type"` // 榜单类型
+	List     []*struct {
+		UID        int64  `json:"uid"`         // 用户mid
+		Uname      string `json:"uname"`       // 用户昵称
+		Face       string `json:"face"`        // 用户头像url
+		Score      string `json:"score"`       // 贡献值
+		Rank       int    `json:"rank"`        // 排名
+		GuardLevel int    `json:"guard_level"` // 0:无 1:总督 2:提督 3:舰长
+	} `json:"list"`
+}
+
+// LiveRoomChange 直播间信息变更 ROOM_CHANGE
+type LiveRoomChange struct {
+	Title          string `json:"title"`            // 直播间标题
+	AreaID         int    `json:"area_id"`          // 子分区ID
+	ParentAreaID   int    `json:"parent_area_id"`   // 父分区ID
+	AreaName       string `json:"area_name"`        // 子分区名
+	ParentAreaName string `json:"parent_area_name"` // 父分区名
+}
+
+// LiveStatusChange 开播 LIVE 与下播 PREPARING
+type LiveStatusChange struct {
+	RoomID   int64 `json:"roomid"`    // 直播间ID
+	LiveTime int64 `json:"live_time"` // 开播时间 时间戳 仅开播消息
+}
+
+// LiveWarning 超管警告 WARNING 与切断直播 CUT_OFF
+type LiveWarning struct {
+	RoomID int64  `json:"roomid"` // 直播间ID
+	Msg    string `json:"msg"`    // 警告或切断原因
+}
 type Comment struct {
 	RPID int64 `json:"rpid"` // 评论rpid
 	OID  int64 `json:"oid"`  // 评论区对象id
@@ -2162,3 +3010,269 @@ type UserInfo struct {
 		ShowUpgradeWindow bool `json:"show_upgrade_window"` //
 	} `json:"series"` //
 }
+type BangumiSeason struct {
+	SeasonID      int64               `json:"season_id"`       // 剧集ssid
+	MediaID       int64               `json:"media_id"`        // 剧集mdid
+	Title         string              `json:"title"`           // 剧集标题
+	SeasonTitle   string              `json:"season_title"`    // 季度标题
+	Alias         string              `json:"alias"`           // 别名 多个用,分隔
+	JpTitle       string              `json:"jp_title"`        // 原名
+	Subtitle      string              `json:"subtitle"`        // 副标题
+	Evaluate      string              `json:"evaluate"`        // 简介
+	Cover         string              `json:"cover"`           // 剧集封面url
+	SquareCover   string              `json:"square_cover"`    // 方形封面url
+	BkgCover      string              `json:"bkg_cover"`       // 背景图url
+	Link          string              `json:"link"`            // 剧集页面url
+	ShareURL      string              `json:"share_url"`       // 分享url
+	Type          int                 `json:"type"`            // 剧集类型 1：番剧 2：电影 3：纪录片 4：国创 5：电视剧 7：综艺
+	Mode          int                 `json:"mode"`            // 1：单集 2：多集
+	Status        int                 `json:"status"`          // 剧集状态 2：免费 13：大会员专享
+	Total         int                 `json:"total"`           // 总集数 未完结为-1
+	Record        string              `json:"record"`          // 备案号
+	Episodes      []*BangumiEpisode   `json:"episodes"`        // 正片分集列表
+	Section       []*BangumiSection   `json:"section"`         // 其他分节(预告、花絮、PV等) 无则为null
+	Seasons       []*BangumiSeasonRel `json:"seasons"`         // 同系列的其他季度
+	NewEp         *BangumiNewEp       `json:"new_ep"`          // 最新一集信息
+	Positive      *BangumiPositive    `json:"positive"`        // 正片分节信息
+	Publish       *BangumiPublish     `json:"publish"`         // 发布信息
+	Rating        *BangumiRating      `json:"rating"`          // 评分信息 无评分则为null
+	Stat          *BangumiStat        `json:"stat"`            // 状态数
+	UpInfo        *BangumiUpInfo      `json:"up_info"`         // 出品方账号信息
+	Series        *BangumiSeries      `json:"series"`          // 系列信息
+	ShareCopy     string              `json:"share_copy"`      // 分享标题
+	ShareSubTitle string              `json:"share_sub_title"` // 分享副标题
+}
+
+// BangumiEpisode 番剧分集
+//
+// 每一集都对应一个普通稿件，AID CID 可以直接用于 VideoGetPlayURL DanmakuGetByPb CommentGetMain(tp传入1，oid传入AID) 等接口
+type BangumiEpisode struct {
+	ID          int64                 `json:"id"`           // 分集epid
+	AID         int64                 `json:"aid"`          // 分集对应稿件avid
+	BVID        string                `json:"bvid"`         // 分集对应稿件bvid
+	CID         int64                 `json:"cid"`          // 分集对应视频cid
+	Title       string                `json:"title"`        // 分集序号 例如 "1"
+	LongTitle   string                `json:"long_title"`   // 分集标题
+	Cover       string                `json:"cover"`        // 分集封面url
+	Duration    int64                 `json:"duration"`     // 分集时长 单位为毫秒
+	PubTime     int64                 `json:"pub_time"`     // 发布时间 时间戳
+	ReleaseDate string                `json:"release_date"` // 空 作用尚不明确
+	Status      int                   `json:"status"`       // 分集状态 2：免费 13：大会员专享
+	Badge       string                `json:"badge"`        // 角标内容 例如 "会员"
+	BadgeType   int                   `json:"badge_type"`   // 角标类型
+	BadgeInfo   *BangumiBadge         `json:"badge_info"`   // 角标信息
+	From        string                `json:"from"`         // 视频来源 bangumi
+	Link        string                `json:"link"`         // 分集页面url
+	ShareURL    string                `json:"share_url"`    // 分享url
+	ShortLink   string                `json:"short_link"`   // 短链接
+	ShareCopy   string                `json:"share_copy"`   // 分享标题
+	Subtitle    string                `json:"subtitle"`     // 观看数文字
+	VID         string                `json:"vid"`          // 空 作用尚不明确
+	IsPremiere  int                   `json:"is_premiere"`  // 是否为首映
+	Dimension   *VideoDimension       `json:"dimension"`    // 分辨率
+	Rights      *BangumiEpisodeRights `json:"rights"`       // 分集属性
+}
+type BangumiEpisodeRights struct {
+	AllowDm       int `json:"allow_dm"`       // 是否允许弹幕
+	AllowDownload int `json:"allow_download"` // 是否允许下载
+	AreaLimit     int `json:"area_limit"`     // 是否有地区限制
+}
+type BangumiBadge struct {
+	BgColor      string `json:"bg_color"`       // 背景颜色 颜色码
+	BgColorNight string `json:"bg_color_night"` // 夜间背景颜色 颜色码
+	Text         string `json:"text"`           // 角标内容
+}
+
+// BangumiSection 分节，例如正片、预告、花絮、PV
+type BangumiSection struct {
+	ID        int64             `json:"id"`         // 分节id
+	Title     string            `json:"title"`      // 分节标题
+	Type      int               `json:"type"`       // 分节类型
+	EpisodeID int64             `json:"episode_id"` // 0
+	Episodes  []*BangumiEpisode `json:"episodes"`   // 分节内分集列表
+}
+type BangumiSectionList struct {
+	MainSection *BangumiSection   `json:"main_section"` // 正片分节
+	Section     []*BangumiSection `json:"section"`      // 其他分节
+}
+type BangumiSeasonRel struct {
+	SeasonID    int64  `json:"season_id"`    // 季度ssid
+	MediaID     int64  `json:"media_id"`     // 季度mdid
+	SeasonTitle string `json:"season_title"` // 季度标题
+	SeasonType  int    `json:"season_type"`  // 剧集类型
+	Cover       string `json:"cover"`        // 封面url
+	Badge       string `json:"badge"`        // 角标内容
+	NewEp       *struct {
+		ID        int64  `json:"id"`         // 最新一集epid
+		Cover     string `json:"cover"`      // 最新一集封面url
+		IndexShow string `json:"index_show"` // 更新至第几集的文字
+	} `json:"new_ep"`
+	Stat *struct {
+		Favorites    int64 `json:"favorites"`     // 追番数
+		SeriesFollow int64 `json:"series_follow"` // 系列追番数
+		Views        int64 `json:"views"`         // 播放数
+	} `json:"stat"`
+}
+type BangumiNewEp struct {
+	ID        int64  `json:"id"`         // 最新一集epid
+	Title     string `json:"title"`      // 最新一集序号
+	Desc      string `json:"desc"`       // 更新状态文字
+	IsNew     int    `json:"is_new"`     // 是否为新
+	Index     string `json:"index"`      // 最新一集序号 仅 BangumiGetMedia 有效
+	IndexShow string `json:"index_show"` // 更新至第几集的文字 仅 BangumiGetMedia 有效
+}
+type BangumiPositive struct {
+	ID    int64  `json:"id"`    // 正片分节id
+	Title string `json:"title"` // 正片分节标题
+}
+type BangumiPublish struct {
+	IsFinish      int    `json:"is_finish"`       // 是否完结
+	IsStarted     int    `json:"is_started"`      // 是否开播
+	PubTime       string `json:"pub_time"`        // 开播时间 YYYY-MM-DD hh:mm:ss
+	PubTimeShow   string `json:"pub_time_show"`   // 开播时间文字
+	UnknowPubDate int    `json:"unknow_pub_date"` // 开播时间是否未知
+	Weekday       int    `json:"weekday"`         // 周几更新 0为周日
+}
+type BangumiRating struct {
+	Count int     `json:"count"` // 评分人数
+	Score float64 `json:"score"` // 评分
+}
+type BangumiStat struct {
+	Coins     int64 `json:"coins"`     // 投币数
+	Danmakus  int64 `json:"danmakus"`  // 弹幕数
+	Favorites int64 `json:"favorites"` // 追番数
+	Likes     int64 `json:"likes"`     // 点赞数
+	Reply     int64 `json:"reply"`     // 评论数
+	Share     int64 `json:"share"`     // 分享数
+	Views     int64 `json:"views"`     // 播放数
+}
+type BangumiUpInfo struct {
+	MID      int64  `json:"mid"`      // 出品方mid
+	Uname    string `json:"uname"`    // 出品方昵称
+	Avatar   string `json:"avatar"`   // 出品方头像url
+	Follower int64  `json:"follower"` // 粉丝数
+}
+type BangumiSeries struct {
+	SeriesID    int64  `json:"series_id"`    // 系列id
+	SeriesTitle string `json:"series_title"` // 系列标题
+}
+type BangumiMedia struct {
+	MediaID  int64  `json:"media_id"`  // 剧集mdid
+	SeasonID int64  `json:"season_id"` // 剧集ssid 用于 BangumiGetSeason
+	Title    string `json:"title"`     // 剧集标题
+	Cover    string `json:"cover"`     // 剧集封面url
+	ShareURL string `json:"share_url"` // 剧集页面url
+	Type     int    `json:"type"`      // 剧集类型 1：番剧 2：电影 3：纪录片 4：国创 5：电视剧 7：综艺
+	TypeName string `json:"type_name"` // 剧集类型文字
+	Areas    []*struct {
+		ID   int    `json:"id"`   // 地区id
+		Name string `json:"name"` // 地区名
+	} `json:"areas"`
+	NewEp  *BangumiNewEp  `json:"new_ep"` // 最新一集信息
+	Rating *BangumiRating `json:"rating"` // 评分信息 无评分则为null
+}
+type BangumiReviews struct {
+	List  []*BangumiReview `json:"list"`  // 评价列表
+	Total int              `json:"total"` // 评价总数
+	// 下一页的游标 用于请求下一页
+	//
+	// 为0时表示已经到底
+	Next int64 `json:"next"`
+}
+type BangumiReview struct {
+	ReviewID int64  `json:"review_id"` // 评价id
+	MediaID  int64  `json:"media_id"`  // 剧集mdid
+	MID      int64  `json:"mid"`       // 评价者mid
+	Title    string `json:"title"`     // 评价标题 仅长评有效
+	Content  string `json:"content"`   // 评价内容 长评为摘要
+	URL      string `json:"url"`       // 长评页面url 仅长评有效
+	Score    int    `json:"score"`     // 评分 区间:[2,10] 即1-5星
+	Progress string `json:"progress"`  // 评价时的观看进度
+	Ctime    int64  `json:"ctime"`     // 评价时间 时间戳
+	Mtime    int64  `json:"mtime"`     // 修改时间 时间戳
+	Author   *struct {
+		MID    int64  `json:"mid"`    // 评价者mid
+		Uname  string `json:"uname"`  // 评价者昵称
+		Avatar string `json:"avatar"` // 评价者头像url
+	} `json:"author"`
+	Stat *struct {
+		Likes    int `json:"likes"`    // 点赞数
+		Disliked int `json:"disliked"` // 是否点踩 需要登录
+		Liked    int `json:"liked"`    // 是否点赞 需要登录
+		Reply    int `json:"reply"`    // 回复数 仅长评有效
+	} `json:"stat"`
+}
+type BangumiTimeline struct {
+	Date      string                    `json:"date"`        // 日期 M-D
+	DateTs    int64                     `json:"date_ts"`     // 日期时间戳
+	DayOfWeek int                       `json:"day_of_week"` // 周几 1-7
+	IsToday   int                       `json:"is_today"`    // 是否为今天
+	Episodes  []*BangumiTimelineEpisode `json:"episodes"`    // 当天更新的分集
+}
+type BangumiTimelineEpisode struct {
+	EpisodeID   int64  `json:"episode_id"`   // 分集epid
+	SeasonID    int64  `json:"season_id"`    // 剧集ssid
+	Title       string `json:"title"`        // 剧集标题
+	PubIndex    string `json:"pub_index"`    // 更新的集数文字
+	PubTime     string `json:"pub_time"`     // 更新时间 hh:mm
+	PubTs       int64  `json:"pub_ts"`       // 更新时间戳
+	Published   int    `json:"published"`    // 是否已更新
+	Delay       int    `json:"delay"`        // 是否延迟更新
+	DelayReason string `json:"delay_reason"` // 延迟原因
+	Cover       string `json:"cover"`        // 剧集封面url
+	EpCover     string `json:"ep_cover"`     // 分集封面url
+	SquareCover string `json:"square_cover"` // 方形封面url
+	Follows     string `json:"follows"`      // 追番数文字
+	Plays       string `json:"plays"`        // 播放数文字
+}
+
+// Subtitle BCC格式字幕 即 VideoSubtitleList.SubtitleURL 指向的JSON文件
+type Subtitle struct {
+	FontSize        float64        `json:"font_size"`         // 字体大小 默认0.4
+	FontColor       string         `json:"font_color"`        // 字体颜色 默认#FFFFFF
+	BackgroundAlpha float64        `json:"background_alpha"`  // 背景不透明度 默认0.5
+	BackgroundColor string         `json:"background_color"`  // 背景颜色 默认#9C27B0
+	Stroke          string         `json:"Stroke"`            // 描边 默认none
+	Type            string         `json:"type,omitempty"`    // 字幕类型 AI字幕为AIsubtitle
+	Lang            string         `json:"lang,omitempty"`    // 字幕语言
+	Version         string         `json:"version,omitempty"` // 字幕版本 如v1.6.0.4
+	Body            []*SubtitleCue `json:"body"`              // 字幕内容
+}
+type SubtitleCue struct {
+	From     float64 `json:"from"`            // 开始时间 单位为秒
+	To       float64 `json:"to"`              // 结束时间 单位为秒
+	SID      int     `json:"sid,omitempty"`   // 序号 从1开始
+	Location int     `json:"location"`        // 位置 与小键盘方位相同 2为底部居中 8为顶部居中
+	Content  string  `json:"content"`         // 内容 多行用\n分隔
+	Music    float64 `json:"music,omitempty"` // 是否为音乐 AI字幕可能存在
+}
+type SubtitleList struct {
+	Page      *SubtitlePage   `json:"page"`      // 分页信息
+	Subtitles []*SubtitleInfo `json:"subtitles"` // 字幕列表
+}
+type SubtitlePage struct {
+	Num   int `json:"num"`   // 当前页码
+	Size  int `json:"size"`  // 每页项数
+	Total int `json:"total"` // 总计项数
+}
+type SubtitleInfo struct {
+	ID            int64  `json:"id"`             // 字幕id
+	IDStr         string `json:"id_str"`         // 字幕id 字符串形式
+	Oid           int64  `json:"oid"`            // 视频cid
+	Type          int    `json:"type"`           // 1：视频
+	Lan           string `json:"lan"`            // 字幕语言
+	LanDoc        string `json:"lan_doc"`        // 字幕语言名称
+	Status        int    `json:"status"`         // 字幕状态 见 SubtitleStatusDraft 等
+	AID           int64  `json:"aid"`            // 稿件avid
+	BVID          string `json:"bvid"`           // 稿件bvid
+	Title         string `json:"title"`          // 稿件标题
+	Part          string `json:"part"`           // 分P标题
+	Cover         string `json:"cover"`          // 稿件封面url
+	AuthorMID     int64  `json:"author_mid"`     // 字幕作者mid
+	UpMID         int64  `json:"up_mid"`         // 稿件UP主mid
+	IsSign        bool   `json:"is_sign"`        // 是否署名
+	IsLock        bool   `json:"is_lock"`        // 是否锁定
+	RejectComment string `json:"reject_comment"` // 驳回理由 未驳回时为空
+	SubtitleURL   string `json:"subtitle_url"`   // BCC格式字幕文件URL
+	Mtime         int64  `json:"mtime"`          // 最后修改时间 时间戳
+}
@@ -0,0 +1,97 @@
+package biligo
+
+import (
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"image"
+	"sort"
+)
+
+// VideoShotFrame 一帧快照在拼版中的位置
+type VideoShotFrame struct {
+	Time  int             // 截取时间 单位为秒
+	Sheet int             // 所在拼版在 VideoShot.Image 中的下标
+	Image string          // 所在拼版URL
+	Rect  image.Rectangle // 在拼版中的裁剪区域
+}
+
+// VideoShotFrames 按截取时间升序排列的快照
+type VideoShotFrames []*VideoShotFrame
+
+// At 获取 sec 秒时应显示的快照，即截取时间不大于 sec 的最后一帧
+//
+// 没有快照时返回nil，sec 早于第一帧时返回第一帧
+func (f VideoShotFrames) At(sec int) *VideoShotFrame {
+	if len(f) == 0 {
+		return nil
+	}
+	i := sort.Search(len(f), func(i int) bool {
+		return f[i].Time > sec
+	})
+	if i == 0 {
+		return f[0]
+	}
+	return f[i-1]
+}
+
+// ParseVideoShotPvdata 解析bin格式截取时间表
+//
+// 每两个字节为一个大端序uint16，单位为秒
+func ParseVideoShotPvdata(data []byte) ([]int, error) {
+	if len(data)%2 != 0 {
+		return nil, errors.New("invalid pvdata length")
+	}
+	times := make([]int, 0, len(data)/2)
+	for i := 0; i < len(data); i += 2 {
+		times = append(times, int(binary.BigEndian.Uint16(data[i:])))
+	}
+	return times, nil
+}
+
+// Frames 将截取时间表映射到拼版中的裁剪区域
+//
+// times 为截取时间表，传入nil时使用 Index
+//
+// 快照按照从左到右 从上到下的顺序排布，一张拼版占满后延续下一张，超出拼版数量的时间会被忽略
+func (s *VideoShot) Frames(times []int) VideoShotFrames {
+	if times == nil {
+		times = s.Index
+	}
+	per := s.ImgXLen * s.ImgYLen
+	if per <= 0 || s.ImgXSize <= 0 || s.ImgYSize <= 0 {
+		return nil
+	}
+
+	frames := make(VideoShotFrames, 0, len(times))
+	for i, t := range times {
+		sheet := i / per
+		if sheet >= len(s.Image) {
+			break
+		}
+		p := i % per
+		x, y := p%s.ImgXLen*s.ImgXSize, p/s.ImgXLen*s.ImgYSize
+		frames = append(frames, &VideoShotFrame{
+			Time:  t,
+			Sheet: sheet,
+			Image: s.Image[sheet],
+			Rect:  image.Rect(x, y, x+s.ImgXSize, y+s.ImgYSize),
+		})
+	}
+	return frames
+}
+
+// CropVideoShotFrame 从已解码的拼版中裁剪出一帧快照
+//
+// 返回的图片与拼版共享像素数据
+func CropVideoShotFrame(sheet image.Image, frame *VideoShotFrame) (image.Image, error) {
+	sub, ok := sheet.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, errors.New("image does not support cropping")
+	}
+	if !frame.Rect.In(sheet.Bounds()) {
+		return nil, errors.New("frame out of sheet bounds")
+	}
+	return sub.SubImage(frame.Rect), nil
+}
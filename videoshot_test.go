@@ -0,0 +1,66 @@
+package biligo
+
+import (
+	"image"
+	"testing"
+)
+
+func TestParseVideoShotPvdata(t *testing.T) {
+	times, err := ParseVideoShotPvdata([]byte{0x00, 0x00, 0x00, 0x03, 0x01, 0x02})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if len(times) != 3 || times[1] != 3 || times[2] != 258 {
+		t.Error(times)
+		t.FailNow()
+	}
+	if _, err = ParseVideoShotPvdata([]byte{0x00}); err == nil {
+		t.Error("want error when length is odd")
+		t.FailNow()
+	}
+}
+func TestVideoShot_Frames(t *testing.T) {
+	shot := &VideoShot{ImgXLen: 2, ImgYLen: 2, ImgXSize: 160, ImgYSize: 90, Image: []string{"a.jpg", "b.jpg"}}
+	frames := shot.Frames([]int{0, 3, 6, 9, 12, 15, 18, 21, 24})
+	// 超出两张拼版的部分被忽略
+	if len(frames) != 8 {
+		t.Error(len(frames))
+		t.FailNow()
+	}
+	if f := frames[3]; f.Sheet != 0 || f.Rect != image.Rect(160, 90, 320, 180) {
+		t.Errorf("%+v", f)
+		t.FailNow()
+	}
+	if f := frames[5]; f.Image != "b.jpg" || f.Rect != image.Rect(160, 0, 320, 90) {
+		t.Errorf("%+v", f)
+		t.FailNow()
+	}
+	if f := frames.At(7); f.Time != 6 {
+		t.Errorf("%+v", f)
+		t.FailNow()
+	}
+	if f := frames.At(-1); f.Time != 0 {
+		t.Errorf("%+v", f)
+		t.FailNow()
+	}
+	if VideoShotFrames(nil).At(1) != nil {
+		t.FailNow()
+	}
+}
+func TestCropVideoShotFrame(t *testing.T) {
+	sheet := image.NewRGBA(image.Rect(0, 0, 320, 180))
+	tile, err := CropVideoShotFrame(sheet, &VideoShotFrame{Rect: image.Rect(160, 90, 320, 180)})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if tile.Bounds() != image.Rect(160, 90, 320, 180) {
+		t.Error(tile.Bounds())
+		t.FailNow()
+	}
+	if _, err = CropVideoShotFrame(sheet, &VideoShotFrame{Rect: image.Rect(320, 0, 480, 90)}); err == nil {
+		t.Error("want error when out of bounds")
+		t.FailNow()
+	}
+}